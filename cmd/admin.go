@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"golangHexagonal/internal/app/model"
+	"os"
+)
+
+type adminCreator interface {
+	BootstrapAdmin(user *model.User) error
+}
+
+// createAdmin handles "create-admin -name NAME -email EMAIL". The password is
+// read from ADMIN_PASSWORD so that it does not end up in shell history.
+func createAdmin(users adminCreator, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	name := flags.String("name", "Admin", "display name of the admin")
+	email := flags.String("email", "", "email address of the admin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if *email == "" || password == "" {
+		return errors.New("create-admin needs -email and the ADMIN_PASSWORD environment variable")
+	}
+
+	admin := &model.User{Name: *name, Email: *email, Password: password}
+	if err := users.BootstrapAdmin(admin); err != nil {
+		return err
+	}
+
+	fmt.Printf("created admin %s (id %d)\n", admin.Email, admin.ID)
+	return nil
+}
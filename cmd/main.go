@@ -1,63 +1,175 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/rand"
 	"fmt"
 	"golangHexagonal/internal/app/handler"
 	"golangHexagonal/internal/app/repository"
 	"golangHexagonal/internal/app/service"
+	"golangHexagonal/internal/config"
+	"golangHexagonal/internal/infrastructure/blocklist"
 	"golangHexagonal/internal/infrastructure/database"
+	"golangHexagonal/internal/infrastructure/hashing"
+	"golangHexagonal/internal/infrastructure/mail"
+	"log"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-type Config struct {
-	DBUser string `json:"db_user"`
-	DBPass string `json:"db_pass"`
-	DBHost string `json:"db_host"`
-	DBPort string `json:"db_port"`
-	DBName string `json:"db_name"`
-}
-
 func main() {
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(handler.RequestIDMiddleware())
 
-	configFile, err := os.Open("config.json")
+	cfg, err := config.Load("config.json")
 	if err != nil {
 		panic(err)
 	}
-	defer func(configFile *os.File) {
-		err := configFile.Close()
-		if err != nil {
 
-		}
-	}(configFile)
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.DBUser, cfg.DBPass, cfg.DBHost, cfg.DBPort, cfg.DBName)
 
-	var config Config
-	err = json.NewDecoder(configFile).Decode(&config)
+	db, err := database.ConnectDB(dsn)
 	if err != nil {
-		return
+		panic(err)
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.DBUser, config.DBPass, config.DBHost, config.DBPort, config.DBName)
-
-	db, err := database.ConnectDB(dsn)
+	keySet, err := service.NewKeySet(cfg.JWT)
 	if err != nil {
 		panic(err)
 	}
 
+	mailer := newMailer(cfg.Mail)
+	hasher := newPasswordHasher(cfg.Password)
+	passwordPolicy, err := newPasswordPolicy(cfg.Password)
+	if err != nil {
+		panic(err)
+	}
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	userHandler := handler.NewUserHandler(userService)
-	jwtService := service.NewJWTService()
-	authHandler := handler.NewAuthHandler(userService, jwtService)
+	oneTimeTokenRepo := repository.NewOneTimeTokenRepository(db)
+
+	verificationService := service.NewEmailVerificationService(userRepo, oneTimeTokenRepo, mailer, cfg.Auth.EmailVerificationURL, cfg.Auth.EmailVerificationTTL.Duration(), cfg.Auth.VerificationResendInterval.Duration())
+	verificationHandler := handler.NewVerificationHandler(verificationService)
+
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo)
+
+	userService := service.NewUserService(userRepo, service.NewOwnershipPolicy(), verificationService, hasher, passwordPolicy, newCursorCodec(cfg.Pagination), sessionService, cfg.Auth.RequireVerifiedEmail)
+	userHandler := handler.NewUserHandler(userService, handler.DefaultUserRouteRoles())
+
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		if err := createAdmin(userService, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var revocationRepo repository.IRevocationRepository
+	if cfg.RevocationStore == "memory" {
+		revocationRepo = repository.NewMemoryRevocationRepository()
+	} else {
+		revocationRepo = repository.NewRevocationRepository(db)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.StartPurger(ctx, "revoked tokens", 15*time.Minute, revocationRepo.PurgeExpiredRevocations)
+
+	userRetention := service.NewUserRetention(userRepo, cfg.Users.DeletedRetention.Duration())
+	service.StartPurger(ctx, "deleted users", time.Hour, userRetention.PurgeDeletedUsers)
+
+	jwtService := service.NewJWTService(keySet, cfg.JWT, revocationRepo)
+	refreshTokenService := service.NewRefreshTokenService(refreshTokenRepo, sessionRepo)
+	mfaService := service.NewMFAService(userRepo, repository.NewRecoveryCodeRepository(db), cfg.Auth.MFAIssuer)
+	loginThrottle := service.NewLoginThrottle(repository.NewLoginAttemptRepository(db), userRepo, cfg.Auth)
+	mfaHandler := handler.NewMFAHandler(mfaService, loginThrottle)
+	service.StartPurger(ctx, "login attempts", 15*time.Minute, loginThrottle.PurgeLoginAttempts)
+	lockoutHandler := handler.NewLockoutHandler(loginThrottle)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	authHandler := handler.NewAuthHandler(userService, jwtService, refreshTokenService, mfaService, loginThrottle, sessionService)
 
-	userHandler.RegisterRoutes(app)
-	authHandler.RegisterRoutes(app)
+	passwordResetService := service.NewPasswordResetService(userRepo, oneTimeTokenRepo, refreshTokenRepo, sessionService, mailer, hasher, passwordPolicy, cfg.Auth.PasswordResetURL, cfg.Auth.PasswordResetTTL.Duration(), cfg.Auth.PasswordResetInterval.Duration())
+	passwordChangeService := service.NewPasswordChangeService(userRepo, hasher, passwordPolicy, sessionService)
+	passwordHandler := handler.NewPasswordHandler(passwordResetService, passwordChangeService)
+
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), userRepo)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	authMiddleware := handler.AuthMiddleware(jwtService, apiKeyService, sessionService)
+
+	userHandler.RegisterRoutes(app, authMiddleware)
+	authHandler.RegisterRoutes(app, authMiddleware)
+	passwordHandler.RegisterRoutes(app, authMiddleware)
+	verificationHandler.RegisterRoutes(app)
+	mfaHandler.RegisterRoutes(app, authMiddleware)
+	lockoutHandler.RegisterRoutes(app, authMiddleware)
+	apiKeyHandler.RegisterRoutes(app, authMiddleware)
+	sessionHandler.RegisterRoutes(app, authMiddleware)
 
 	err = app.Listen(":3000")
 	if err != nil {
 		return
 	}
 }
+
+func newMailer(cfg config.MailConfig) service.Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return mail.NewSMTPMailer(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From)
+	case "memory":
+		return mail.NewMemoryMailer()
+	default:
+		return mail.NewFileMailer(cfg.FilePath)
+	}
+}
+
+// newPasswordHasher hashes new passwords with the configured algorithm and
+// keeps verifying hashes of the other one.
+func newPasswordHasher(cfg config.PasswordConfig) service.PasswordHasher {
+	bcrypt := hashing.NewBcrypt(cfg.BcryptCost)
+	argon2id := hashing.NewArgon2id(hashing.Argon2idParams{
+		Time:      cfg.Argon2Time,
+		MemoryKiB: cfg.Argon2MemoryKiB,
+		Threads:   cfg.Argon2Threads,
+	})
+
+	if cfg.Algorithm == "bcrypt" {
+		return hashing.NewHasher(bcrypt, argon2id)
+	}
+	return hashing.NewHasher(argon2id, bcrypt)
+}
+
+// newCursorCodec signs list cursors with the configured secret, or with a
+// random one when none is set.
+func newCursorCodec(cfg config.PaginationConfig) *service.CursorCodec {
+	secret := cfg.CursorSecret
+	if cfg.CursorSecretEnv != "" {
+		secret = os.Getenv(cfg.CursorSecretEnv)
+	}
+	if secret != "" {
+		return service.NewCursorCodec([]byte(secret))
+	}
+
+	log.Print("no cursor secret configured; list cursors will not survive a restart")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return service.NewCursorCodec(key)
+}
+
+// newPasswordPolicy loads the breached-password list, if one is configured,
+// into the password policy.
+func newPasswordPolicy(cfg config.PasswordConfig) (*service.PasswordPolicy, error) {
+	if cfg.BreachedPasswordsFile == "" {
+		return service.NewPasswordPolicy(cfg, nil), nil
+	}
+
+	breached, err := blocklist.LoadFile(cfg.BreachedPasswordsFile)
+	if err != nil {
+		return nil, fmt.Errorf("load breached passwords: %w", err)
+	}
+	return service.NewPasswordPolicy(cfg, breached), nil
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"golangHexagonal/internal/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyActions interface {
+	CreateAPIKey(userID uint, input *model.CreateAPIKeyInput) (string, *model.APIKey, error)
+	ListAPIKeys(userID uint) ([]*model.APIKey, error)
+	RevokeAPIKey(userID, id uint) error
+}
+
+type APIKeyHandler struct {
+	apiKeyService APIKeyActions
+}
+
+func NewAPIKeyHandler(apiKeyService APIKeyActions) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// RegisterRoutes mounts the API key routes of the caller's own account. They
+// need an interactive login, so a key cannot be used to mint more keys.
+func (h *APIKeyHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
+	keys := app.Group("/me/api-keys", auth, RequireSession())
+	keys.Post("/", h.CreateAPIKey)
+	keys.Get("/", h.ListAPIKeys)
+	keys.Delete("/:id", h.RevokeAPIKey)
+}
+
+// CreateAPIKey returns the key in clear text. This is the only time it is
+// shown.
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	var input model.CreateAPIKeyInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	key, record, err := h.apiKeyService.CreateAPIKey(claims.UserID, &input)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"key": key, "api_key": apiKeyResponse(record)})
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	response := make([]fiber.Map, len(keys))
+	for i, key := range keys {
+		response[i] = apiKeyResponse(key)
+	}
+
+	return c.JSON(fiber.Map{"api_keys": response})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return problem(c, fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(claims.UserID, uint(id)); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func apiKeyResponse(key *model.APIKey) fiber.Map {
+	return fiber.Map{
+		"id":           key.ID,
+		"name":         key.Name,
+		"prefix":       key.Prefix,
+		"scopes":       key.ScopeList(),
+		"expires_at":   key.ExpiresAt,
+		"last_used_at": key.LastUsedAt,
+		"revoked_at":   key.RevokedAt,
+		"created_at":   key.CreatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	create := func(t *testing.T, mockAPIKeyActions APIKeyActions, input *model.CreateAPIKeyInput) (int, map[string]interface{}) {
+		body, err := json.Marshal(input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/me/api-keys", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		apiKeyHandler := NewAPIKeyHandler(mockAPIKeyActions)
+		apiKeyHandler.RegisterRoutes(app, withClaims(userClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		var respBody map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&respBody)
+		return resp.StatusCode, respBody
+	}
+
+	t.Run("should return the key once without its hash", func(t *testing.T) {
+		input := &model.CreateAPIKeyInput{Name: "ci", Scopes: []string{model.ScopeUsersRead}}
+
+		mockAPIKeyActions := mocks.NewMockAPIKeyActions(ctrl)
+		mockAPIKeyActions.EXPECT().CreateAPIKey(userClaims.UserID, input).Return("hxk_secret", &model.APIKey{
+			ID:      1,
+			Name:    "ci",
+			Prefix:  "hxk_secret",
+			KeyHash: "hash",
+			Scopes:  model.ScopeUsersRead,
+		}, nil)
+
+		status, respBody := create(t, mockAPIKeyActions, input)
+		assert.Equal(t, 201, status)
+
+		assert.Equal(t, "hxk_secret", respBody["key"])
+		apiKey := respBody["api_key"].(map[string]interface{})
+		assert.Equal(t, []interface{}{model.ScopeUsersRead}, apiKey["scopes"])
+		assert.NotContains(t, apiKey, "key_hash")
+	})
+
+	t.Run("should return 422 for an unknown scope", func(t *testing.T) {
+		input := &model.CreateAPIKeyInput{Name: "ci", Scopes: []string{"admin:everything"}}
+
+		status, respBody := create(t, mocks.NewMockAPIKeyActions(ctrl), input)
+		assert.Equal(t, 422, status)
+		assert.Contains(t, respBody, "violations")
+	})
+
+	t.Run("should return 422 when the service rejects the scopes", func(t *testing.T) {
+		input := &model.CreateAPIKeyInput{Name: "ci", Scopes: []string{model.ScopeUsersRead}}
+
+		mockAPIKeyActions := mocks.NewMockAPIKeyActions(ctrl)
+		mockAPIKeyActions.EXPECT().CreateAPIKey(userClaims.UserID, input).Return("", nil, service.ErrInvalidScope)
+
+		status, _ := create(t, mockAPIKeyActions, input)
+		assert.Equal(t, 422, status)
+	})
+}
+
+func TestHandler_RevokeAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revoke := func(t *testing.T, mockAPIKeyActions APIKeyActions) int {
+		app := newTestApp()
+
+		apiKeyHandler := NewAPIKeyHandler(mockAPIKeyActions)
+		apiKeyHandler.RegisterRoutes(app, withClaims(userClaims))
+
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/me/api-keys/3", nil))
+		assert.Nil(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("should return 204 when key is revoked", func(t *testing.T) {
+		mockAPIKeyActions := mocks.NewMockAPIKeyActions(ctrl)
+		mockAPIKeyActions.EXPECT().RevokeAPIKey(userClaims.UserID, uint(3)).Return(nil)
+
+		assert.Equal(t, 204, revoke(t, mockAPIKeyActions))
+	})
+
+	t.Run("should return 404 for a key of another user", func(t *testing.T) {
+		mockAPIKeyActions := mocks.NewMockAPIKeyActions(ctrl)
+		mockAPIKeyActions.EXPECT().RevokeAPIKey(userClaims.UserID, uint(3)).Return(service.ErrAPIKeyNotFound)
+
+		assert.Equal(t, 404, revoke(t, mockAPIKeyActions))
+	})
+}
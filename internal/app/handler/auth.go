@@ -1,54 +1,263 @@
 package handler
 
 import (
+	"errors"
 	"golangHexagonal/internal/app/model"
 	"golangHexagonal/internal/app/service"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
 )
 
 type AuthActions interface {
 	AuthenticateUser(email, password string) (*model.User, error)
+	GetUserByID(id uint) (*model.User, error)
 }
 
 type JWTActions interface {
-	GenerateToken(userID uint) (string, error)
+	GenerateToken(user *model.User, sessionID string) (string, error)
 	VerifyToken(tokenString string) (*service.Claims, error)
+	RevokeToken(claims *service.Claims) error
+	JWKS() service.JWKSet
+	GenerateMFAChallenge(user *model.User) (string, error)
+	VerifyMFAChallenge(tokenString string) (*service.Claims, error)
+}
+
+type RefreshActions interface {
+	IssueRefreshToken(userID uint, sessionID string) (string, error)
+	RotateRefreshToken(token string) (*model.RefreshToken, string, error)
+}
+
+type SessionStarter interface {
+	StartSession(userID uint, userAgent, ip string) (*model.Session, error)
+	RevokeSession(userID uint, id string) error
+}
+
+type MFAVerifier interface {
+	VerifyMFA(user *model.User, code string) error
+}
+
+// LoginThrottleActions limits wrong passwords and wrong codes for MFA login
+// challenges. RecordChallengeFailure reports whether the challenge has used
+// up its attempts.
+type LoginThrottleActions interface {
+	AllowLogin(email, ip string) (time.Duration, error)
+	RecordLoginFailure(email, ip string) error
+	RecordLoginSuccess(email string) error
+	AllowMFA(userID uint, ip string) (time.Duration, error)
+	RecordMFAFailure(userID uint, ip string) error
+	RecordMFASuccess(userID uint) error
+	RecordChallengeFailure(challengeID string) (bool, error)
 }
 
 type AuthHandler struct {
-	userService AuthActions
-	jwtService  JWTActions
+	userService     AuthActions
+	jwtService      JWTActions
+	refreshService  RefreshActions
+	mfaService      MFAVerifier
+	throttleService LoginThrottleActions
+	sessionService  SessionStarter
 }
 
-func NewAuthHandler(userService AuthActions, jwtActions JWTActions) *AuthHandler {
-	return &AuthHandler{userService: userService, jwtService: jwtActions}
+func NewAuthHandler(userService AuthActions, jwtActions JWTActions, refreshActions RefreshActions, mfaVerifier MFAVerifier, throttle LoginThrottleActions, sessions SessionStarter) *AuthHandler {
+	return &AuthHandler{
+		userService:     userService,
+		jwtService:      jwtActions,
+		refreshService:  refreshActions,
+		mfaService:      mfaVerifier,
+		throttleService: throttle,
+		sessionService:  sessions,
+	}
 }
 
-func (h *AuthHandler) RegisterRoutes(app *fiber.App) {
+// RegisterRoutes mounts the auth routes. The MFA step is limited per client
+// IP on top of the per-account throttle in LoginMFA.
+func (h *AuthHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
 	app.Post("/login", h.Login)
-	app.Post("/logout", h.Logout)
+	app.Post("/login/mfa", limiter.New(limiter.Config{
+		Max:        10,
+		Expiration: 5 * time.Minute,
+		LimitReached: func(c *fiber.Ctx) error {
+			return problem(c, fiber.StatusTooManyRequests, "Too many requests")
+		},
+	}), h.LoginMFA)
+	app.Post("/logout", auth, RequireSession(), h.Logout)
+	app.Post("/token/refresh", h.Refresh)
+	app.Get("/.well-known/jwks.json", h.JWKS)
 }
 
+// Login answers a throttled or locked out email with 429 before looking at
+// the password. Unknown emails are throttled the same way as real accounts,
+// so the response does not tell them apart.
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var input model.LoginInput
-	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	wait, err := h.throttleService.AllowLogin(input.Email, c.IP())
+	if err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not check login attempts")
+	}
+	if wait > 0 {
+		return tooManyAttempts(c, wait, "Too many failed login attempts")
 	}
 
 	user, err := h.userService.AuthenticateUser(input.Email, input.Password)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid email or password"})
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			return problem(c, fiber.StatusForbidden, "Email address is not verified")
+		}
+		if err := h.throttleService.RecordLoginFailure(input.Email, c.IP()); err != nil {
+			return problem(c, fiber.StatusInternalServerError, "Could not record login attempt")
+		}
+		return problem(c, fiber.StatusUnauthorized, "Invalid email or password")
+	}
+
+	if err := h.throttleService.RecordLoginSuccess(input.Email); err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not record login attempt")
+	}
+
+	if user.MFAEnabled {
+		challenge, err := h.jwtService.GenerateMFAChallenge(user)
+		if err != nil {
+			return problem(c, fiber.StatusInternalServerError, "Could not generate token")
+		}
+		return c.JSON(fiber.Map{"mfa_required": true, "challenge_token": challenge})
+	}
+
+	return h.issueTokens(c, user)
+}
+
+// tooManyAttempts answers a throttled request with 429 and the time to wait
+// in Retry-After.
+func tooManyAttempts(c *fiber.Ctx, wait time.Duration, detail string) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	return problem(c, fiber.StatusTooManyRequests, detail)
+}
+
+// LoginMFA completes a login started with a challenge token by checking a
+// TOTP or recovery code. Wrong codes are throttled per account like
+// passwords, and a challenge is revoked once it has been used or has taken
+// too many wrong codes.
+func (h *AuthHandler) LoginMFA(c *fiber.Ctx) error {
+	var input model.MFALoginInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	claims, err := h.jwtService.VerifyMFAChallenge(input.ChallengeToken)
+	if err != nil {
+		return problem(c, fiber.StatusUnauthorized, "Invalid or expired challenge")
+	}
+
+	user, err := h.userService.GetUserByID(claims.UserID)
+	if err != nil {
+		return problem(c, fiber.StatusUnauthorized, "Invalid or expired challenge")
+	}
+
+	answered, err := throttleMFA(c, h.throttleService, user.ID, func() error {
+		return h.mfaService.VerifyMFA(user, input.Code)
+	})
+	if errors.Is(err, service.ErrInvalidMFACode) {
+		return h.rejectMFACode(c, claims)
+	}
+	if answered || err != nil {
+		return err
+	}
+
+	if err := h.jwtService.RevokeToken(claims); err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not revoke token")
+	}
+
+	return h.issueTokens(c, user)
+}
+
+// rejectMFACode answers a wrong code with 401 and revokes the challenge once
+// it has taken too many of them.
+func (h *AuthHandler) rejectMFACode(c *fiber.Ctx, challenge *service.Claims) error {
+	spent, err := h.throttleService.RecordChallengeFailure(challenge.ID)
+	if err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not record login attempt")
+	}
+	if spent {
+		if err := h.jwtService.RevokeToken(challenge); err != nil {
+			return problem(c, fiber.StatusInternalServerError, "Could not revoke token")
+		}
+	}
+	return problem(c, fiber.StatusUnauthorized, "Invalid code")
+}
+
+// issueTokens starts a session for the client and returns its first access
+// and refresh tokens.
+func (h *AuthHandler) issueTokens(c *fiber.Ctx, user *model.User) error {
+	session, err := h.sessionService.StartSession(user.ID, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not start session")
+	}
+
+	token, err := h.jwtService.GenerateToken(user, session.ID)
+	if err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not generate token")
+	}
+
+	refreshToken, err := h.refreshService.IssueRefreshToken(user.ID, session.ID)
+	if err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not generate token")
+	}
+
+	return c.JSON(fiber.Map{"token": token, "refresh_token": refreshToken})
+}
+
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var input model.RefreshInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	rotated, refreshToken, err := h.refreshService.RotateRefreshToken(input.RefreshToken)
+	if err != nil {
+		return problem(c, fiber.StatusUnauthorized, "Invalid refresh token")
+	}
+
+	user, err := h.userService.GetUserByID(rotated.UserID)
+	if err != nil {
+		return problem(c, fiber.StatusUnauthorized, "Invalid refresh token")
 	}
 
-	token, err := h.jwtService.GenerateToken(user.ID)
+	token, err := h.jwtService.GenerateToken(user, rotated.FamilyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not generate token"})
+		return problem(c, fiber.StatusInternalServerError, "Could not generate token")
 	}
 
-	return c.JSON(fiber.Map{"token": token})
+	return c.JSON(fiber.Map{"token": token, "refresh_token": refreshToken})
 }
 
+// Logout revokes the access token and ends its session, which also revokes
+// the refresh tokens of the session.
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	if err := h.jwtService.RevokeToken(claims); err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not revoke token")
+	}
+
+	if err := h.sessionService.RevokeSession(claims.UserID, claims.SessionID); err != nil && !errors.Is(err, service.ErrSessionNotFound) {
+		return problem(c, fiber.StatusInternalServerError, "Could not end session")
+	}
+
 	return c.JSON(fiber.Map{"message": "Logout"})
 }
+
+// JWKS publishes the public verification keys so that other services can
+// validate tokens without calling this one.
+func (h *AuthHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.jwtService.JWKS())
+}
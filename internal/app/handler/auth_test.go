@@ -7,8 +7,11 @@ import (
 
 	"golangHexagonal/internal/app/handler/mocks"
 	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
@@ -34,7 +37,9 @@ func TestHandler_Login(t *testing.T) {
 			Password: reqBody.Password,
 			Name:     gomock.Any().String(),
 		}, nil)
-		mokcJWTActions.EXPECT().GenerateToken(gomock.Any()).Return("token", nil)
+		mokcJWTActions.EXPECT().GenerateToken(gomock.Any(), "session").Return("token", nil)
+		mockRefreshActions := mocks.NewMockRefreshActions(ctrl)
+		mockRefreshActions.EXPECT().IssueRefreshToken(uint(1), "session").Return("refresh", nil)
 
 		body, err := json.Marshal(&reqBody)
 		assert.Nil(t, err)
@@ -42,25 +47,32 @@ func TestHandler_Login(t *testing.T) {
 		req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
-		app := fiber.New()
+		app := newTestApp()
 
-		authHandler := NewAuthHandler(mockAuthActions, mokcJWTActions)
-		authHandler.RegisterRoutes(app)
+		authHandler := NewAuthHandler(mockAuthActions, mokcJWTActions, mockRefreshActions, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
 
 		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody map[string]string
+		err = json.NewDecoder(resp.Body).Decode(&respBody)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "token", respBody["token"])
+		assert.Equal(t, "refresh", respBody["refresh_token"])
 	})
 
 	t.Run("should return 400 when request body is invalid", func(t *testing.T) {
 		req := httptest.NewRequest("POST", "/login", bytes.NewReader([]byte("invalid json")))
 		req.Header.Set("Content-Type", "application/json")
 
-		app := fiber.New()
+		app := newTestApp()
 
-		authHandler := NewAuthHandler(nil, nil)
-		authHandler.RegisterRoutes(app)
+		authHandler := NewAuthHandler(nil, nil, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -83,10 +95,10 @@ func TestHandler_Login(t *testing.T) {
 		req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
-		app := fiber.New()
+		app := newTestApp()
 
-		authHandler := NewAuthHandler(mockAuthActions, nil)
-		authHandler.RegisterRoutes(app)
+		authHandler := NewAuthHandler(mockAuthActions, nil, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -94,6 +106,32 @@ func TestHandler_Login(t *testing.T) {
 		assert.Equal(t, 401, resp.StatusCode)
 	})
 
+	t.Run("should return 403 when email is not verified", func(t *testing.T) {
+		reqBody := model.LoginInput{
+			Email:    "test@gmail.com",
+			Password: "123456",
+		}
+
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockAuthActions.EXPECT().AuthenticateUser(reqBody.Email, reqBody.Password).Return(nil, service.ErrEmailNotVerified)
+
+		body, err := json.Marshal(&reqBody)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(mockAuthActions, nil, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
 	t.Run("should return 500 when generate token failed", func(t *testing.T) {
 		reqBody := model.LoginInput{
 			Email:    "test@gmail.com",
@@ -108,7 +146,7 @@ func TestHandler_Login(t *testing.T) {
 			Password: reqBody.Password,
 			Name:     gomock.Any().String(),
 		}, nil)
-		mockJWTActions.EXPECT().GenerateToken(gomock.Any()).Return("", errors.New("failed to generate token"))
+		mockJWTActions.EXPECT().GenerateToken(gomock.Any(), "session").Return("", errors.New("failed to generate token"))
 
 		body, err := json.Marshal(&reqBody)
 		assert.Nil(t, err)
@@ -116,10 +154,10 @@ func TestHandler_Login(t *testing.T) {
 		req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
-		app := fiber.New()
+		app := newTestApp()
 
-		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions)
-		authHandler.RegisterRoutes(app)
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -128,18 +166,381 @@ func TestHandler_Login(t *testing.T) {
 	})
 }
 
+func TestHandler_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newRequest := func(t *testing.T, refreshToken string) *http.Request {
+		body, err := json.Marshal(&model.RefreshInput{RefreshToken: refreshToken})
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/token/refresh", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("should return 200 with a rotated token pair", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockRefreshActions := mocks.NewMockRefreshActions(ctrl)
+		mockRefreshActions.EXPECT().RotateRefreshToken("old").Return(&model.RefreshToken{UserID: 1, FamilyID: "session"}, "new", nil)
+		mockAuthActions.EXPECT().GetUserByID(uint(1)).Return(&model.User{ID: 1}, nil)
+		mockJWTActions.EXPECT().GenerateToken(&model.User{ID: 1}, "session").Return("token", nil)
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, mockRefreshActions, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(newRequest(t, "old"))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody map[string]string
+		err = json.NewDecoder(resp.Body).Decode(&respBody)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "token", respBody["token"])
+		assert.Equal(t, "new", respBody["refresh_token"])
+	})
+
+	t.Run("should return 400 when request body is invalid", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/token/refresh", bytes.NewReader([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(nil, nil, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when refresh token is rejected", func(t *testing.T) {
+		mockRefreshActions := mocks.NewMockRefreshActions(ctrl)
+		mockRefreshActions.EXPECT().RotateRefreshToken("reused").Return(nil, "", errors.New("refresh token reuse detected"))
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(nil, nil, mockRefreshActions, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(newRequest(t, "reused"))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
 func TestHandler_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	t.Run("should return 200 when logout success", func(t *testing.T) {
+		claims := &service.Claims{UserID: 1}
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().RevokeToken(claims).Return(nil)
+
 		req := httptest.NewRequest("POST", "/logout", nil)
 
-		app := fiber.New()
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(nil, mockJWTActions, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, withClaims(claims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when logout without a token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/logout", nil)
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(nil, nil, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, AuthMiddleware(nil, nil, nil))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should return 500 when revoke fails", func(t *testing.T) {
+		claims := &service.Claims{UserID: 1}
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().RevokeToken(claims).Return(errors.New("error"))
+
+		req := httptest.NewRequest("POST", "/logout", nil)
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(nil, mockJWTActions, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, withClaims(claims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 500, resp.StatusCode)
+	})
+}
+
+func TestHandler_JWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should return the public key set", func(t *testing.T) {
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().JWKS().Return(service.JWKSet{Keys: []service.JWK{{KeyType: "OKP", KeyID: "ed-1"}}})
+
+		req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+		app := newTestApp()
+
+		authHandler := NewAuthHandler(nil, mockJWTActions, nil, nil, allowAll{}, fixedSession{})
+		authHandler.RegisterRoutes(app, noAuth)
 
-		authHandler := NewAuthHandler(nil, nil)
-		authHandler.RegisterRoutes(app)
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody service.JWKSet
+		err = json.NewDecoder(resp.Body).Decode(&respBody)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "ed-1", respBody.Keys[0].KeyID)
+	})
+}
+
+func TestHandler_LoginMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &model.User{ID: 1, Email: "test@gmail.com", MFAEnabled: true}
+	challenge := &service.Claims{UserID: 1}
+
+	post := func(t *testing.T, authHandler *AuthHandler, path string, input interface{}) *http.Response {
+		body, err := json.Marshal(input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+		authHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("should return a challenge instead of tokens when mfa is enabled", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockAuthActions.EXPECT().AuthenticateUser("test@gmail.com", "123456").Return(user, nil)
+		mockJWTActions.EXPECT().GenerateMFAChallenge(user).Return("challenge", nil)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, nil, nil, allowAll{}, fixedSession{})
+		resp := post(t, authHandler, "/login", model.LoginInput{Email: "test@gmail.com", Password: "123456"})
+
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody map[string]interface{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, true, respBody["mfa_required"])
+		assert.Equal(t, "challenge", respBody["challenge_token"])
+		assert.NotContains(t, respBody, "token")
+	})
+
+	t.Run("should issue tokens for a valid code and revoke the challenge", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockRefreshActions := mocks.NewMockRefreshActions(ctrl)
+		mockMFAVerifier := mocks.NewMockMFAVerifier(ctrl)
+
+		mockJWTActions.EXPECT().VerifyMFAChallenge("challenge").Return(challenge, nil)
+		mockAuthActions.EXPECT().GetUserByID(uint(1)).Return(user, nil)
+		mockMFAVerifier.EXPECT().VerifyMFA(user, "123456").Return(nil)
+		mockJWTActions.EXPECT().RevokeToken(challenge).Return(nil)
+		mockJWTActions.EXPECT().GenerateToken(user, "session").Return("token", nil)
+		mockRefreshActions.EXPECT().IssueRefreshToken(uint(1), "session").Return("refresh", nil)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, mockRefreshActions, mockMFAVerifier, allowAll{}, fixedSession{})
+		resp := post(t, authHandler, "/login/mfa", model.MFALoginInput{ChallengeToken: "challenge", Code: "123456"})
+
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody map[string]string
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, "token", respBody["token"])
+		assert.Equal(t, "refresh", respBody["refresh_token"])
+	})
+
+	t.Run("should return 401 when code is invalid", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockMFAVerifier := mocks.NewMockMFAVerifier(ctrl)
+
+		mockJWTActions.EXPECT().VerifyMFAChallenge("challenge").Return(challenge, nil)
+		mockAuthActions.EXPECT().GetUserByID(uint(1)).Return(user, nil)
+		mockMFAVerifier.EXPECT().VerifyMFA(user, "000000").Return(service.ErrInvalidMFACode)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, nil, mockMFAVerifier, allowAll{}, fixedSession{})
+		resp := post(t, authHandler, "/login/mfa", model.MFALoginInput{ChallengeToken: "challenge", Code: "000000"})
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should revoke the challenge after too many wrong codes", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockMFAVerifier := mocks.NewMockMFAVerifier(ctrl)
+		mockThrottle := mocks.NewMockLoginThrottleActions(ctrl)
+
+		mockJWTActions.EXPECT().VerifyMFAChallenge("challenge").Return(challenge, nil)
+		mockAuthActions.EXPECT().GetUserByID(uint(1)).Return(user, nil)
+		mockThrottle.EXPECT().AllowMFA(uint(1), gomock.Any()).Return(time.Duration(0), nil)
+		mockMFAVerifier.EXPECT().VerifyMFA(user, "000000").Return(service.ErrInvalidMFACode)
+		mockThrottle.EXPECT().RecordMFAFailure(uint(1), gomock.Any()).Return(nil)
+		mockThrottle.EXPECT().RecordChallengeFailure(challenge.ID).Return(true, nil)
+		mockJWTActions.EXPECT().RevokeToken(challenge).Return(nil)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, nil, mockMFAVerifier, mockThrottle, fixedSession{})
+		resp := post(t, authHandler, "/login/mfa", model.MFALoginInput{ChallengeToken: "challenge", Code: "000000"})
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should return 429 without checking the code while the account is throttled", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockThrottle := mocks.NewMockLoginThrottleActions(ctrl)
+
+		mockJWTActions.EXPECT().VerifyMFAChallenge("challenge").Return(challenge, nil)
+		mockAuthActions.EXPECT().GetUserByID(uint(1)).Return(user, nil)
+		mockThrottle.EXPECT().AllowMFA(uint(1), gomock.Any()).Return(1500*time.Millisecond, nil)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, nil, mocks.NewMockMFAVerifier(ctrl), mockThrottle, fixedSession{})
+		resp := post(t, authHandler, "/login/mfa", model.MFALoginInput{ChallengeToken: "challenge", Code: "123456"})
+
+		assert.Equal(t, 429, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("should return 409 when mfa was disabled during the challenge", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockMFAVerifier := mocks.NewMockMFAVerifier(ctrl)
+
+		mockJWTActions.EXPECT().VerifyMFAChallenge("challenge").Return(challenge, nil)
+		mockAuthActions.EXPECT().GetUserByID(uint(1)).Return(user, nil)
+		mockMFAVerifier.EXPECT().VerifyMFA(user, "123456").Return(service.ErrMFANotEnabled)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, nil, mockMFAVerifier, allowAll{}, fixedSession{})
+		resp := post(t, authHandler, "/login/mfa", model.MFALoginInput{ChallengeToken: "challenge", Code: "123456"})
+
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when challenge is invalid", func(t *testing.T) {
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().VerifyMFAChallenge("access").Return(nil, service.ErrWrongTokenPurpose)
+
+		authHandler := NewAuthHandler(nil, mockJWTActions, nil, nil, allowAll{}, fixedSession{})
+		resp := post(t, authHandler, "/login/mfa", model.MFALoginInput{ChallengeToken: "access", Code: "123456"})
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
+func TestHandler_LoginThrottle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	login := func(t *testing.T, authHandler *AuthHandler) *http.Response {
+		body, err := json.Marshal(&model.LoginInput{Email: "test@gmail.com", Password: "123456"})
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+		authHandler.RegisterRoutes(app, noAuth)
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("should return 429 without checking the password while throttled", func(t *testing.T) {
+		mockThrottle := mocks.NewMockLoginThrottleActions(ctrl)
+		mockThrottle.EXPECT().AllowLogin("test@gmail.com", gomock.Any()).Return(1500*time.Millisecond, nil)
+
+		authHandler := NewAuthHandler(nil, nil, nil, nil, mockThrottle, fixedSession{})
+		resp := login(t, authHandler)
+
+		assert.Equal(t, 429, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("should record a failure when the password is wrong", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockThrottle := mocks.NewMockLoginThrottleActions(ctrl)
+		mockThrottle.EXPECT().AllowLogin("test@gmail.com", gomock.Any()).Return(time.Duration(0), nil)
+		mockAuthActions.EXPECT().AuthenticateUser("test@gmail.com", "123456").Return(nil, errors.New("invalid credentials"))
+		mockThrottle.EXPECT().RecordLoginFailure("test@gmail.com", gomock.Any()).Return(nil)
+
+		authHandler := NewAuthHandler(mockAuthActions, nil, nil, nil, mockThrottle, fixedSession{})
+		resp := login(t, authHandler)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should clear failures after a successful login", func(t *testing.T) {
+		mockAuthActions := mocks.NewMockAuthActions(ctrl)
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockRefreshActions := mocks.NewMockRefreshActions(ctrl)
+		mockThrottle := mocks.NewMockLoginThrottleActions(ctrl)
+		mockThrottle.EXPECT().AllowLogin("test@gmail.com", gomock.Any()).Return(time.Duration(0), nil)
+		mockAuthActions.EXPECT().AuthenticateUser("test@gmail.com", "123456").Return(&model.User{ID: 1}, nil)
+		mockThrottle.EXPECT().RecordLoginSuccess("test@gmail.com").Return(nil)
+		mockJWTActions.EXPECT().GenerateToken(gomock.Any(), "session").Return("token", nil)
+		mockRefreshActions.EXPECT().IssueRefreshToken(uint(1), "session").Return("refresh", nil)
+
+		authHandler := NewAuthHandler(mockAuthActions, mockJWTActions, mockRefreshActions, nil, mockThrottle, fixedSession{})
+		resp := login(t, authHandler)
 
 		assert.Equal(t, 200, resp.StatusCode)
 	})
 }
+
+// allowAll is a LoginThrottleActions that never throttles.
+type allowAll struct{}
+
+func (allowAll) AllowLogin(email, ip string) (time.Duration, error) { return 0, nil }
+func (allowAll) RecordLoginFailure(email, ip string) error          { return nil }
+func (allowAll) RecordLoginSuccess(email string) error              { return nil }
+func (allowAll) AllowMFA(userID uint, ip string) (time.Duration, error) {
+	return 0, nil
+}
+func (allowAll) RecordMFAFailure(userID uint, ip string) error { return nil }
+func (allowAll) RecordMFASuccess(userID uint) error            { return nil }
+func (allowAll) RecordChallengeFailure(challengeID string) (bool, error) {
+	return false, nil
+}
+
+// fixedSession is a SessionStarter that always starts the session "session".
+type fixedSession struct{}
+
+func (fixedSession) StartSession(userID uint, userAgent, ip string) (*model.Session, error) {
+	return &model.Session{ID: "session", UserID: userID}, nil
+}
+
+func (fixedSession) RevokeSession(userID uint, id string) error { return nil }
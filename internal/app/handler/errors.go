@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"golangHexagonal/internal/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorStatuses maps the domain error kinds to HTTP status codes.
+var errorStatuses = []struct {
+	kind   error
+	status int
+}{
+	{model.ErrNotFound, fiber.StatusNotFound},
+	{model.ErrConflict, fiber.StatusConflict},
+	{model.ErrUnauthorized, fiber.StatusUnauthorized},
+	{model.ErrForbidden, fiber.StatusForbidden},
+	{model.ErrValidation, fiber.StatusUnprocessableEntity},
+	{model.ErrPreconditionFailed, fiber.StatusPreconditionFailed},
+}
+
+// ErrorHandler is the Fiber error handler of the API. Handlers return
+// service errors as they are and it answers with a problem of the status of
+// their kind.
+// Errors of no known kind become a bare 500 and are only logged, so that
+// database messages never reach clients.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return problem(c, fiberErr.Code, fiberErr.Message)
+	}
+
+	if ok, err := asValidationError(c, err); ok {
+		return err
+	}
+
+	for _, mapping := range errorStatuses {
+		if errors.Is(err, mapping.kind) {
+			p := newProblem(c, mapping.status, err.Error())
+			if mapping.kind == model.ErrValidation {
+				p.Type = ProblemTypeValidation
+			}
+			return writeProblem(c, p)
+		}
+	}
+
+	p := newProblem(c, fiber.StatusInternalServerError, "")
+	log.Printf("%s %s (request %s): %v", c.Method(), c.Path(), p.RequestID, err)
+	return writeProblem(c, p)
+}
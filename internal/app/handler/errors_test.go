@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ErrorHandler(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not found", model.NewError(model.ErrNotFound, "user not found"), 404},
+		{"conflict", model.NewError(model.ErrConflict, "user already exists"), 409},
+		{"unauthorized", service.ErrInvalidCredentials, 401},
+		{"forbidden", service.ErrForbidden, 403},
+		{"precondition", model.ErrUserModified, 412},
+		{"validation", &service.ValidationError{Violations: []service.FieldViolation{{Field: "name", Code: "required"}}}, 422},
+		{"wrapped domain", fmt.Errorf("find user: %w", model.NewError(model.ErrNotFound, "user not found")), 404},
+		{"fiber", fiber.ErrMethodNotAllowed, 405},
+		{"unknown", errors.New("Error 1213: Deadlock found when trying to get lock"), 500},
+	} {
+		t.Run("should map a "+tc.name+" error", func(t *testing.T) {
+			app := newTestApp()
+			app.Get("/", func(c *fiber.Ctx) error { return tc.err })
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			assert.Nil(t, err)
+
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+
+	t.Run("should not leak the message of an unknown error", func(t *testing.T) {
+		app := newTestApp()
+		app.Get("/", func(c *fiber.Ctx) error { return errors.New("Error 1054: Unknown column 'pasword'") })
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		assert.Nil(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		assert.NotContains(t, string(body), "1054")
+	})
+}
+
+func TestHandler_ProblemDetails(t *testing.T) {
+	t.Run("should answer with RFC 7807 problem details and the request id", func(t *testing.T) {
+		app := newTestApp()
+		app.Use(RequestIDMiddleware())
+		app.Get("/users/:id", func(c *fiber.Ctx) error {
+			return model.NewError(model.ErrNotFound, "user not found")
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/users/9", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, MIMEProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+
+		var p Problem
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&p))
+		assert.Equal(t, Problem{
+			Type:      ProblemTypeDefault,
+			Title:     "Not Found",
+			Status:    404,
+			Detail:    "user not found",
+			Instance:  "/users/9",
+			RequestID: resp.Header.Get(fiber.HeaderXRequestID),
+		}, p)
+		assert.NotEmpty(t, p.RequestID)
+	})
+
+	t.Run("should list violations in a validation problem", func(t *testing.T) {
+		app := newTestApp()
+		app.Post("/", func(c *fiber.Ctx) error {
+			var input model.CreateUserInput
+			if err := parseBody(c, &input); err != nil {
+				return bodyError(c, err)
+			}
+			return nil
+		})
+
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"email":"test@gmail.com","password":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		var p Problem
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&p))
+		assert.Equal(t, ProblemTypeValidation, p.Type)
+		assert.Equal(t, 422, p.Status)
+		assert.Equal(t, []service.FieldViolation{{Field: "name", Code: "required", Message: "is required"}}, p.Violations)
+	})
+
+	t.Run("should report unknown routes as problems", func(t *testing.T) {
+		resp, err := newTestApp().Test(httptest.NewRequest("GET", "/missing", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, MIMEProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+	})
+}
+
+func TestHandler_UserErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should return 404 when the user does not exist", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().GetUserByID(uint(9)).Return(nil, model.NewError(model.ErrNotFound, "user not found"))
+
+		app := newTestApp()
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/users/9", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("should return 409 when the email is taken", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().CreateUser(gomock.Any()).Return(model.NewError(model.ErrConflict, "user already exists"))
+
+		app := newTestApp()
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"test","email":"test@gmail.com","password":"secret password"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"golangHexagonal/internal/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setUserETag tags the response with the version of user.
+func setUserETag(c *fiber.Ctx, user *model.User) {
+	c.Set(fiber.HeaderETag, `"`+strconv.FormatUint(uint64(user.Version), 10)+`"`)
+}
+
+// ifMatchVersion returns the user version named by the If-Match header. A
+// missing header and "*", which would match any version, are answered with
+// 428, so that writes cannot skip the check. Only a single strong ETag as
+// sent by setUserETag can match; anything else is answered with 412.
+func ifMatchVersion(c *fiber.Ctx) (uint, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, fiber.NewError(fiber.StatusPreconditionRequired, "Send the ETag of the user in If-Match")
+	}
+	if header == "*" {
+		return 0, fiber.NewError(fiber.StatusPreconditionRequired, "If-Match must name the ETag of the user, not *")
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+	version, err := strconv.ParseUint(tag, 10, 0)
+	if !ok || err != nil || version == 0 {
+		return 0, fiber.NewError(fiber.StatusPreconditionFailed, "If-Match does not match the user")
+	}
+	return uint(version), nil
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"golangHexagonal/internal/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LockoutActions interface {
+	UnlockAccount(userID uint) error
+}
+
+type LockoutHandler struct {
+	lockoutService LockoutActions
+}
+
+func NewLockoutHandler(lockoutService LockoutActions) *LockoutHandler {
+	return &LockoutHandler{lockoutService: lockoutService}
+}
+
+// RegisterRoutes mounts the admin-only lockout routes. API keys need the
+// write scope to unlock accounts.
+func (h *LockoutHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
+	app.Post("/admin/users/:id/unlock", auth, RequireScope(model.ScopeUsersWrite), RequireRole(model.RoleAdmin), h.UnlockAccount)
+}
+
+// UnlockAccount clears the failed login attempts of an account so that its
+// owner can log in again before the lockout expires.
+func (h *LockoutHandler) UnlockAccount(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return problem(c, fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	if err := h.lockoutService.UnlockAccount(uint(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"message": "Account unlocked"})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_UnlockAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should unlock the account for an admin", func(t *testing.T) {
+		mockLockoutActions := mocks.NewMockLockoutActions(ctrl)
+		mockLockoutActions.EXPECT().UnlockAccount(uint(2)).Return(nil)
+
+		app := newTestApp()
+
+		lockoutHandler := NewLockoutHandler(mockLockoutActions)
+		lockoutHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/admin/users/2/unlock", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return 403 for a regular user", func(t *testing.T) {
+		mockLockoutActions := mocks.NewMockLockoutActions(ctrl)
+
+		app := newTestApp()
+
+		lockoutHandler := NewLockoutHandler(mockLockoutActions)
+		lockoutHandler.RegisterRoutes(app, withClaims(userClaims))
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/admin/users/2/unlock", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("should return 403 for an admin api key without the write scope", func(t *testing.T) {
+		mockLockoutActions := mocks.NewMockLockoutActions(ctrl)
+		keyClaims := &service.Claims{UserID: 1, Role: model.RoleAdmin, APIKeyID: 7, Scopes: []string{model.ScopeUsersRead}}
+
+		app := newTestApp()
+
+		lockoutHandler := NewLockoutHandler(mockLockoutActions)
+		lockoutHandler.RegisterRoutes(app, withClaims(keyClaims))
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/admin/users/2/unlock", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MFAActions interface {
+	BeginEnrollment(userID uint) (*model.MFAEnrollment, error)
+	ConfirmEnrollment(userID uint, code string) ([]string, error)
+	DisableMFA(userID uint, code string) error
+}
+
+// MFAThrottle limits wrong second-factor codes like LoginThrottleActions
+// limits wrong passwords.
+type MFAThrottle interface {
+	AllowMFA(userID uint, ip string) (time.Duration, error)
+	RecordMFAFailure(userID uint, ip string) error
+	RecordMFASuccess(userID uint) error
+}
+
+type MFAHandler struct {
+	mfaService MFAActions
+	throttle   MFAThrottle
+}
+
+func NewMFAHandler(mfaService MFAActions, throttle MFAThrottle) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService, throttle: throttle}
+}
+
+// RegisterRoutes mounts the enrollment routes. They always act on the
+// authenticated caller's own account. The routes that check a code are
+// throttled like login.
+func (h *MFAHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
+	mfa := app.Group("/me/mfa", auth, RequireSession())
+	mfa.Post("/enroll", h.BeginEnrollment)
+	mfa.Post("/confirm", h.ConfirmEnrollment)
+	mfa.Post("/disable", h.DisableMFA)
+}
+
+func (h *MFAHandler) BeginEnrollment(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(enrollment)
+}
+
+// ConfirmEnrollment returns the recovery codes. This is the only time they
+// are shown.
+func (h *MFAHandler) ConfirmEnrollment(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	var input model.MFACodeInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	var codes []string
+	answered, err := throttleMFA(c, h.throttle, claims.UserID, func() error {
+		var err error
+		codes, err = h.mfaService.ConfirmEnrollment(claims.UserID, input.Code)
+		return err
+	})
+	if answered || err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"recovery_codes": codes})
+}
+
+func (h *MFAHandler) DisableMFA(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	var input model.MFACodeInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	answered, err := throttleMFA(c, h.throttle, claims.UserID, func() error {
+		return h.mfaService.DisableMFA(claims.UserID, input.Code)
+	})
+	if answered || err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"message": "Two-factor authentication disabled"})
+}
+
+// throttleMFA runs check unless the caller has to wait after earlier wrong
+// codes, in which case it answers the request with 429 itself and reports
+// true. A wrong code counts as a failure and a correct one clears them.
+func throttleMFA(c *fiber.Ctx, throttle MFAThrottle, userID uint, check func() error) (bool, error) {
+	wait, err := throttle.AllowMFA(userID, c.IP())
+	if err != nil {
+		return false, err
+	}
+	if wait > 0 {
+		return true, tooManyAttempts(c, wait, "Too many wrong codes")
+	}
+
+	err = check()
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		if err := throttle.RecordMFAFailure(userID, c.IP()); err != nil {
+			return false, err
+		}
+		return false, err
+	case err == nil:
+		return false, throttle.RecordMFASuccess(userID)
+	default:
+		return false, err
+	}
+}
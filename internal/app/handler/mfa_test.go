@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_MFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	postThrottled := func(t *testing.T, mockMFAActions MFAActions, throttle MFAThrottle, path string, input interface{}) *http.Response {
+		body, err := json.Marshal(input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		mfaHandler := NewMFAHandler(mockMFAActions, throttle)
+		mfaHandler.RegisterRoutes(app, withClaims(userClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	post := func(t *testing.T, mockMFAActions MFAActions, path string, input interface{}) *http.Response {
+		return postThrottled(t, mockMFAActions, allowAll{}, path, input)
+	}
+
+	t.Run("should start enrollment for the caller", func(t *testing.T) {
+		mockMFAActions := mocks.NewMockMFAActions(ctrl)
+		mockMFAActions.EXPECT().BeginEnrollment(userClaims.UserID).Return(&model.MFAEnrollment{Secret: "SECRET", URI: "otpauth://totp/x"}, nil)
+
+		resp := post(t, mockMFAActions, "/me/mfa/enroll", nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody map[string]string
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, "SECRET", respBody["secret"])
+		assert.Equal(t, "otpauth://totp/x", respBody["otpauth_uri"])
+	})
+
+	t.Run("should return recovery codes when enrollment is confirmed", func(t *testing.T) {
+		mockMFAActions := mocks.NewMockMFAActions(ctrl)
+		mockMFAActions.EXPECT().ConfirmEnrollment(userClaims.UserID, "123456").Return([]string{"abcde-fghjk"}, nil)
+
+		resp := post(t, mockMFAActions, "/me/mfa/confirm", model.MFACodeInput{Code: "123456"})
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody map[string][]string
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, []string{"abcde-fghjk"}, respBody["recovery_codes"])
+	})
+
+	t.Run("should return 422 when confirmation code is invalid", func(t *testing.T) {
+		mockMFAActions := mocks.NewMockMFAActions(ctrl)
+		mockMFAActions.EXPECT().ConfirmEnrollment(userClaims.UserID, "000000").Return(nil, service.ErrInvalidMFACode)
+
+		resp := post(t, mockMFAActions, "/me/mfa/confirm", model.MFACodeInput{Code: "000000"})
+		assert.Equal(t, 422, resp.StatusCode)
+	})
+
+	t.Run("should count a wrong code as a failed attempt", func(t *testing.T) {
+		mockMFAActions := mocks.NewMockMFAActions(ctrl)
+		mockMFAActions.EXPECT().DisableMFA(userClaims.UserID, "000000").Return(service.ErrInvalidMFACode)
+		mockThrottle := mocks.NewMockMFAThrottle(ctrl)
+		mockThrottle.EXPECT().AllowMFA(userClaims.UserID, gomock.Any()).Return(time.Duration(0), nil)
+		mockThrottle.EXPECT().RecordMFAFailure(userClaims.UserID, gomock.Any()).Return(nil)
+
+		resp := postThrottled(t, mockMFAActions, mockThrottle, "/me/mfa/disable", model.MFACodeInput{Code: "000000"})
+		assert.Equal(t, 422, resp.StatusCode)
+	})
+
+	t.Run("should clear the failures after a correct code", func(t *testing.T) {
+		mockMFAActions := mocks.NewMockMFAActions(ctrl)
+		mockMFAActions.EXPECT().ConfirmEnrollment(userClaims.UserID, "123456").Return([]string{"abcde-fghjk"}, nil)
+		mockThrottle := mocks.NewMockMFAThrottle(ctrl)
+		mockThrottle.EXPECT().AllowMFA(userClaims.UserID, gomock.Any()).Return(time.Duration(0), nil)
+		mockThrottle.EXPECT().RecordMFASuccess(userClaims.UserID).Return(nil)
+
+		resp := postThrottled(t, mockMFAActions, mockThrottle, "/me/mfa/confirm", model.MFACodeInput{Code: "123456"})
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return 429 without checking the code while throttled", func(t *testing.T) {
+		mockThrottle := mocks.NewMockMFAThrottle(ctrl)
+		mockThrottle.EXPECT().AllowMFA(userClaims.UserID, gomock.Any()).Return(1500*time.Millisecond, nil).Times(2)
+
+		for _, path := range []string{"/me/mfa/confirm", "/me/mfa/disable"} {
+			resp := postThrottled(t, mocks.NewMockMFAActions(ctrl), mockThrottle, path, model.MFACodeInput{Code: "123456"})
+			assert.Equal(t, 429, resp.StatusCode, path)
+			assert.Equal(t, "2", resp.Header.Get("Retry-After"), path)
+
+			var respBody Problem
+			assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, "Too many wrong codes", respBody.Detail, path)
+		}
+	})
+
+	t.Run("should return 409 when disabling mfa that is not enabled", func(t *testing.T) {
+		mockMFAActions := mocks.NewMockMFAActions(ctrl)
+		mockMFAActions.EXPECT().DisableMFA(userClaims.UserID, "123456").Return(service.ErrMFANotEnabled)
+
+		resp := post(t, mockMFAActions, "/me/mfa/disable", model.MFACodeInput{Code: "123456"})
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"golangHexagonal/internal/app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClaimsKey is the fiber.Ctx locals key under which AuthMiddleware stores
+// the verified *service.Claims.
+const ClaimsKey = "claims"
+
+// apiKeyHeader is the header scripts can send an API key in, as an
+// alternative to "Authorization: ApiKey <key>".
+const apiKeyHeader = "X-API-Key"
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*service.Claims, error)
+}
+
+type SessionChecker interface {
+	CheckSession(id string) error
+}
+
+// AuthMiddleware rejects requests without a valid Bearer token or API key and
+// stores the verified claims in the context locals for downstream handlers.
+// Access tokens must belong to a session that has not been revoked.
+func AuthMiddleware(jwtService JWTActions, apiKeys APIKeyAuthenticator, sessions SessionChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if key, ok := apiKey(c); ok {
+			claims, err := apiKeys.AuthenticateAPIKey(key)
+			if err != nil {
+				return problem(c, fiber.StatusUnauthorized, "Invalid API key")
+			}
+
+			c.Locals(ClaimsKey, claims)
+			return c.Next()
+		}
+
+		token, ok := bearerToken(c)
+		if !ok {
+			return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+		}
+
+		claims, err := jwtService.VerifyToken(token)
+		if err != nil {
+			return problem(c, fiber.StatusUnauthorized, "Invalid or expired token")
+		}
+
+		if claims.SessionID == "" {
+			return problem(c, fiber.StatusUnauthorized, "Invalid or expired token")
+		}
+		if err := sessions.CheckSession(claims.SessionID); err != nil {
+			if errors.Is(err, service.ErrSessionRevoked) {
+				return problem(c, fiber.StatusUnauthorized, "Session has been revoked")
+			}
+			return problem(c, fiber.StatusInternalServerError, "Could not check session")
+		}
+
+		c.Locals(ClaimsKey, claims)
+		return c.Next()
+	}
+}
+
+// RequireRole only lets through callers whose claims carry one of roles. It
+// must run after AuthMiddleware. With no roles it allows every caller.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(roles) == 0 {
+			return c.Next()
+		}
+
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				return c.Next()
+			}
+		}
+
+		return problem(c, fiber.StatusForbidden, "Insufficient role")
+	}
+}
+
+// RequireScope only lets through callers allowed to use scope. Access tokens
+// carry every scope; API keys only those they were created with. It must run
+// after AuthMiddleware.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+		}
+
+		if !claims.HasScope(scope) {
+			return problem(c, fiber.StatusForbidden, "Insufficient scope")
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireSession refuses callers authenticated with an API key, for routes
+// that manage credentials and must not be reachable from a script.
+func RequireSession() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+		}
+
+		if claims.APIKeyID != 0 {
+			return problem(c, fiber.StatusForbidden, "Not allowed with an API key")
+		}
+
+		return c.Next()
+	}
+}
+
+// ClaimsFromContext returns the claims stored by AuthMiddleware.
+func ClaimsFromContext(c *fiber.Ctx) (*service.Claims, bool) {
+	claims, ok := c.Locals(ClaimsKey).(*service.Claims)
+	return claims, ok && claims != nil
+}
+
+// ActorFromContext describes the authenticated caller for service-level
+// authorization. Without claims it returns the zero Actor, which policies
+// treat as anonymous.
+func ActorFromContext(c *fiber.Ctx) service.Actor {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return service.Actor{}
+	}
+	return service.Actor{UserID: claims.UserID, Role: claims.Role}
+}
+
+func apiKey(c *fiber.Ctx) (string, bool) {
+	if key := strings.TrimSpace(c.Get(apiKeyHeader)); key != "" {
+		return key, true
+	}
+
+	scheme, key, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "ApiKey") {
+		return "", false
+	}
+
+	key = strings.TrimSpace(key)
+	return key, key != ""
+}
+
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_Auth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newApp := func(jwtActions JWTActions) *fiber.App {
+		app := newTestApp()
+		app.Get("/protected", AuthMiddleware(jwtActions, nil, activeSessions{}), func(c *fiber.Ctx) error {
+			claims, ok := ClaimsFromContext(c)
+			if !ok {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			return c.JSON(fiber.Map{"user_id": claims.UserID})
+		})
+		return app
+	}
+
+	t.Run("should return 200 and expose claims when token is valid", func(t *testing.T) {
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().VerifyToken("token").Return(&service.Claims{UserID: 1, SessionID: "session"}, nil)
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := newApp(mockJWTActions).Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when the session was revoked", func(t *testing.T) {
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().VerifyToken("token").Return(&service.Claims{UserID: 1, SessionID: "revoked"}, nil)
+		mockSessions := mocks.NewMockSessionChecker(ctrl)
+		mockSessions.EXPECT().CheckSession("revoked").Return(service.ErrSessionRevoked)
+
+		app := newTestApp()
+		app.Get("/protected", AuthMiddleware(mockJWTActions, nil, mockSessions), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when token has no session", func(t *testing.T) {
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().VerifyToken("token").Return(&service.Claims{UserID: 1}, nil)
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := newApp(mockJWTActions).Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when token is missing", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/protected", nil)
+
+		resp, err := newApp(nil).Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when scheme is not bearer", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+		resp, err := newApp(nil).Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should return 401 when token is invalid", func(t *testing.T) {
+		mockJWTActions := mocks.NewMockJWTActions(ctrl)
+		mockJWTActions.EXPECT().VerifyToken("expired").Return(nil, errors.New("token is expired"))
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer expired")
+
+		resp, err := newApp(mockJWTActions).Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
+func TestMiddleware_APIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keyClaims := &service.Claims{UserID: 2, Role: model.RoleUser, APIKeyID: 7, Scopes: []string{model.ScopeUsersRead}}
+
+	newApp := func(apiKeys APIKeyAuthenticator) *fiber.App {
+		app := newTestApp()
+		app.Get("/protected", AuthMiddleware(nil, apiKeys, nil), func(c *fiber.Ctx) error {
+			claims, ok := ClaimsFromContext(c)
+			if !ok {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			return c.JSON(fiber.Map{"user_id": claims.UserID})
+		})
+		return app
+	}
+
+	for _, header := range []struct{ name, value string }{
+		{"X-API-Key", "hxk_key"},
+		{"Authorization", "ApiKey hxk_key"},
+	} {
+		t.Run("should accept a key in the "+header.name+" header", func(t *testing.T) {
+			mockAPIKeys := mocks.NewMockAPIKeyAuthenticator(ctrl)
+			mockAPIKeys.EXPECT().AuthenticateAPIKey("hxk_key").Return(keyClaims, nil)
+
+			req := httptest.NewRequest("GET", "/protected", nil)
+			req.Header.Set(header.name, header.value)
+
+			resp, err := newApp(mockAPIKeys).Test(req)
+			assert.Nil(t, err)
+
+			assert.Equal(t, 200, resp.StatusCode)
+		})
+	}
+
+	t.Run("should return 401 when key is invalid", func(t *testing.T) {
+		mockAPIKeys := mocks.NewMockAPIKeyAuthenticator(ctrl)
+		mockAPIKeys.EXPECT().AuthenticateAPIKey("hxk_revoked").Return(nil, service.ErrInvalidAPIKey)
+
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("X-API-Key", "hxk_revoked")
+
+		resp, err := newApp(mockAPIKeys).Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("should limit a key to its scopes", func(t *testing.T) {
+		app := newTestApp()
+		app.Get("/read", withClaims(keyClaims), RequireScope(model.ScopeUsersRead), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		app.Get("/write", withClaims(keyClaims), RequireScope(model.ScopeUsersWrite), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/read", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		resp, err = app.Test(httptest.NewRequest("GET", "/write", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("should refuse a key on session-only routes", func(t *testing.T) {
+		app := newTestApp()
+		app.Get("/session", withClaims(keyClaims), RequireSession(), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/session", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
+
+func TestMiddleware_RequireRole(t *testing.T) {
+	newApp := func(claims *service.Claims, roles ...string) *fiber.App {
+		app := newTestApp()
+		app.Get("/admin", withClaims(claims), RequireRole(roles...), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	t.Run("should return 200 when role is allowed", func(t *testing.T) {
+		resp, err := newApp(adminClaims, model.RoleAdmin).Test(httptest.NewRequest("GET", "/admin", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return 403 when role is not allowed", func(t *testing.T) {
+		resp, err := newApp(userClaims, model.RoleAdmin).Test(httptest.NewRequest("GET", "/admin", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("should return 200 for any caller when no role is required", func(t *testing.T) {
+		resp, err := newApp(userClaims).Test(httptest.NewRequest("GET", "/admin", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+}
+
+func TestHandler_UserRoutesRequireAuth(t *testing.T) {
+	t.Run("should return 401 when listing users without a token", func(t *testing.T) {
+		app := newTestApp()
+
+		userHandler := NewUserHandler(nil, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, AuthMiddleware(nil, nil, nil))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
+var adminClaims = &service.Claims{UserID: 1, Role: model.RoleAdmin}
+
+var adminActor = service.Actor{UserID: 1, Role: model.RoleAdmin}
+
+var userClaims = &service.Claims{UserID: 2, Role: model.RoleUser}
+
+// newTestApp returns an app with the API's error handler.
+func newTestApp() *fiber.App {
+	return fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+}
+
+func noAuth(c *fiber.Ctx) error {
+	return c.Next()
+}
+
+// activeSessions is a SessionChecker that accepts every session.
+type activeSessions struct{}
+
+func (activeSessions) CheckSession(id string) error { return nil }
+
+func withClaims(claims *service.Claims) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(ClaimsKey, claims)
+		return c.Next()
+	}
+}
+
+func TestHandler_UserRoutesRequireAdmin(t *testing.T) {
+	for _, tc := range []struct{ method, path string }{
+		{"GET", "/users"},
+		{"DELETE", "/users/2"},
+	} {
+		t.Run("should return 403 for non-admin on "+tc.method+" "+tc.path, func(t *testing.T) {
+			app := newTestApp()
+
+			userHandler := NewUserHandler(nil, DefaultUserRouteRoles())
+			userHandler.RegisterRoutes(app, withClaims(userClaims))
+
+			resp, err := app.Test(httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Nil(t, err)
+
+			assert.Equal(t, 403, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/api_key.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAPIKeyActions is a mock of APIKeyActions interface.
+type MockAPIKeyActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyActionsMockRecorder
+}
+
+// MockAPIKeyActionsMockRecorder is the mock recorder for MockAPIKeyActions.
+type MockAPIKeyActionsMockRecorder struct {
+	mock *MockAPIKeyActions
+}
+
+// NewMockAPIKeyActions creates a new mock instance.
+func NewMockAPIKeyActions(ctrl *gomock.Controller) *MockAPIKeyActions {
+	mock := &MockAPIKeyActions{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyActions) EXPECT() *MockAPIKeyActionsMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyActions) CreateAPIKey(userID uint, input *model.CreateAPIKeyInput) (string, *model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", userID, input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*model.APIKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyActionsMockRecorder) CreateAPIKey(userID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyActions)(nil).CreateAPIKey), userID, input)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPIKeyActions) ListAPIKeys(userID uint) ([]*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", userID)
+	ret0, _ := ret[0].([]*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIKeyActionsMockRecorder) ListAPIKeys(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKeyActions)(nil).ListAPIKeys), userID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyActions) RevokeAPIKey(userID, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyActionsMockRecorder) RevokeAPIKey(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyActions)(nil).RevokeAPIKey), userID, id)
+}
@@ -8,6 +8,7 @@ import (
 	model "golangHexagonal/internal/app/model"
 	service "golangHexagonal/internal/app/service"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -50,6 +51,21 @@ func (mr *MockAuthActionsMockRecorder) AuthenticateUser(email, password interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateUser", reflect.TypeOf((*MockAuthActions)(nil).AuthenticateUser), email, password)
 }
 
+// GetUserByID mocks base method.
+func (m *MockAuthActions) GetUserByID(id uint) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", id)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthActionsMockRecorder) GetUserByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthActions)(nil).GetUserByID), id)
+}
+
 // MockJWTActions is a mock of JWTActions interface.
 type MockJWTActions struct {
 	ctrl     *gomock.Controller
@@ -73,19 +89,77 @@ func (m *MockJWTActions) EXPECT() *MockJWTActionsMockRecorder {
 	return m.recorder
 }
 
+// GenerateMFAChallenge mocks base method.
+func (m *MockJWTActions) GenerateMFAChallenge(user *model.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateMFAChallenge", user)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateMFAChallenge indicates an expected call of GenerateMFAChallenge.
+func (mr *MockJWTActionsMockRecorder) GenerateMFAChallenge(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateMFAChallenge", reflect.TypeOf((*MockJWTActions)(nil).GenerateMFAChallenge), user)
+}
+
 // GenerateToken mocks base method.
-func (m *MockJWTActions) GenerateToken(userID uint) (string, error) {
+func (m *MockJWTActions) GenerateToken(user *model.User, sessionID string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", userID)
+	ret := m.ctrl.Call(m, "GenerateToken", user, sessionID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockJWTActionsMockRecorder) GenerateToken(userID interface{}) *gomock.Call {
+func (mr *MockJWTActionsMockRecorder) GenerateToken(user, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockJWTActions)(nil).GenerateToken), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockJWTActions)(nil).GenerateToken), user, sessionID)
+}
+
+// JWKS mocks base method.
+func (m *MockJWTActions) JWKS() service.JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(service.JWKSet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockJWTActionsMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockJWTActions)(nil).JWKS))
+}
+
+// RevokeToken mocks base method.
+func (m *MockJWTActions) RevokeToken(claims *service.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockJWTActionsMockRecorder) RevokeToken(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockJWTActions)(nil).RevokeToken), claims)
+}
+
+// VerifyMFAChallenge mocks base method.
+func (m *MockJWTActions) VerifyMFAChallenge(tokenString string) (*service.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFAChallenge", tokenString)
+	ret0, _ := ret[0].(*service.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFAChallenge indicates an expected call of VerifyMFAChallenge.
+func (mr *MockJWTActionsMockRecorder) VerifyMFAChallenge(tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFAChallenge", reflect.TypeOf((*MockJWTActions)(nil).VerifyMFAChallenge), tokenString)
 }
 
 // VerifyToken mocks base method.
@@ -102,3 +176,270 @@ func (mr *MockJWTActionsMockRecorder) VerifyToken(tokenString interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockJWTActions)(nil).VerifyToken), tokenString)
 }
+
+// MockRefreshActions is a mock of RefreshActions interface.
+type MockRefreshActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshActionsMockRecorder
+}
+
+// MockRefreshActionsMockRecorder is the mock recorder for MockRefreshActions.
+type MockRefreshActionsMockRecorder struct {
+	mock *MockRefreshActions
+}
+
+// NewMockRefreshActions creates a new mock instance.
+func NewMockRefreshActions(ctrl *gomock.Controller) *MockRefreshActions {
+	mock := &MockRefreshActions{ctrl: ctrl}
+	mock.recorder = &MockRefreshActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshActions) EXPECT() *MockRefreshActionsMockRecorder {
+	return m.recorder
+}
+
+// IssueRefreshToken mocks base method.
+func (m *MockRefreshActions) IssueRefreshToken(userID uint, sessionID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueRefreshToken", userID, sessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueRefreshToken indicates an expected call of IssueRefreshToken.
+func (mr *MockRefreshActionsMockRecorder) IssueRefreshToken(userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueRefreshToken", reflect.TypeOf((*MockRefreshActions)(nil).IssueRefreshToken), userID, sessionID)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockRefreshActions) RotateRefreshToken(token string) (*model.RefreshToken, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", token)
+	ret0, _ := ret[0].(*model.RefreshToken)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockRefreshActionsMockRecorder) RotateRefreshToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockRefreshActions)(nil).RotateRefreshToken), token)
+}
+
+// MockSessionStarter is a mock of SessionStarter interface.
+type MockSessionStarter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionStarterMockRecorder
+}
+
+// MockSessionStarterMockRecorder is the mock recorder for MockSessionStarter.
+type MockSessionStarterMockRecorder struct {
+	mock *MockSessionStarter
+}
+
+// NewMockSessionStarter creates a new mock instance.
+func NewMockSessionStarter(ctrl *gomock.Controller) *MockSessionStarter {
+	mock := &MockSessionStarter{ctrl: ctrl}
+	mock.recorder = &MockSessionStarterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionStarter) EXPECT() *MockSessionStarterMockRecorder {
+	return m.recorder
+}
+
+// RevokeSession mocks base method.
+func (m *MockSessionStarter) RevokeSession(userID uint, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockSessionStarterMockRecorder) RevokeSession(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockSessionStarter)(nil).RevokeSession), userID, id)
+}
+
+// StartSession mocks base method.
+func (m *MockSessionStarter) StartSession(userID uint, userAgent, ip string) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSession", userID, userAgent, ip)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSession indicates an expected call of StartSession.
+func (mr *MockSessionStarterMockRecorder) StartSession(userID, userAgent, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSession", reflect.TypeOf((*MockSessionStarter)(nil).StartSession), userID, userAgent, ip)
+}
+
+// MockMFAVerifier is a mock of MFAVerifier interface.
+type MockMFAVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAVerifierMockRecorder
+}
+
+// MockMFAVerifierMockRecorder is the mock recorder for MockMFAVerifier.
+type MockMFAVerifierMockRecorder struct {
+	mock *MockMFAVerifier
+}
+
+// NewMockMFAVerifier creates a new mock instance.
+func NewMockMFAVerifier(ctrl *gomock.Controller) *MockMFAVerifier {
+	mock := &MockMFAVerifier{ctrl: ctrl}
+	mock.recorder = &MockMFAVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAVerifier) EXPECT() *MockMFAVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyMFA mocks base method.
+func (m *MockMFAVerifier) VerifyMFA(user *model.User, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", user, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockMFAVerifierMockRecorder) VerifyMFA(user, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockMFAVerifier)(nil).VerifyMFA), user, code)
+}
+
+// MockLoginThrottleActions is a mock of LoginThrottleActions interface.
+type MockLoginThrottleActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleActionsMockRecorder
+}
+
+// MockLoginThrottleActionsMockRecorder is the mock recorder for MockLoginThrottleActions.
+type MockLoginThrottleActionsMockRecorder struct {
+	mock *MockLoginThrottleActions
+}
+
+// NewMockLoginThrottleActions creates a new mock instance.
+func NewMockLoginThrottleActions(ctrl *gomock.Controller) *MockLoginThrottleActions {
+	mock := &MockLoginThrottleActions{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleActions) EXPECT() *MockLoginThrottleActionsMockRecorder {
+	return m.recorder
+}
+
+// AllowLogin mocks base method.
+func (m *MockLoginThrottleActions) AllowLogin(email, ip string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowLogin", email, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllowLogin indicates an expected call of AllowLogin.
+func (mr *MockLoginThrottleActionsMockRecorder) AllowLogin(email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowLogin", reflect.TypeOf((*MockLoginThrottleActions)(nil).AllowLogin), email, ip)
+}
+
+// AllowMFA mocks base method.
+func (m *MockLoginThrottleActions) AllowMFA(userID uint, ip string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowMFA", userID, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllowMFA indicates an expected call of AllowMFA.
+func (mr *MockLoginThrottleActionsMockRecorder) AllowMFA(userID, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowMFA", reflect.TypeOf((*MockLoginThrottleActions)(nil).AllowMFA), userID, ip)
+}
+
+// RecordChallengeFailure mocks base method.
+func (m *MockLoginThrottleActions) RecordChallengeFailure(challengeID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordChallengeFailure", challengeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordChallengeFailure indicates an expected call of RecordChallengeFailure.
+func (mr *MockLoginThrottleActionsMockRecorder) RecordChallengeFailure(challengeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordChallengeFailure", reflect.TypeOf((*MockLoginThrottleActions)(nil).RecordChallengeFailure), challengeID)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockLoginThrottleActions) RecordLoginFailure(email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockLoginThrottleActionsMockRecorder) RecordLoginFailure(email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockLoginThrottleActions)(nil).RecordLoginFailure), email, ip)
+}
+
+// RecordLoginSuccess mocks base method.
+func (m *MockLoginThrottleActions) RecordLoginSuccess(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginSuccess", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLoginSuccess indicates an expected call of RecordLoginSuccess.
+func (mr *MockLoginThrottleActionsMockRecorder) RecordLoginSuccess(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginSuccess", reflect.TypeOf((*MockLoginThrottleActions)(nil).RecordLoginSuccess), email)
+}
+
+// RecordMFAFailure mocks base method.
+func (m *MockLoginThrottleActions) RecordMFAFailure(userID uint, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFAFailure", userID, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMFAFailure indicates an expected call of RecordMFAFailure.
+func (mr *MockLoginThrottleActionsMockRecorder) RecordMFAFailure(userID, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFAFailure", reflect.TypeOf((*MockLoginThrottleActions)(nil).RecordMFAFailure), userID, ip)
+}
+
+// RecordMFASuccess mocks base method.
+func (m *MockLoginThrottleActions) RecordMFASuccess(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFASuccess", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMFASuccess indicates an expected call of RecordMFASuccess.
+func (mr *MockLoginThrottleActionsMockRecorder) RecordMFASuccess(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFASuccess", reflect.TypeOf((*MockLoginThrottleActions)(nil).RecordMFASuccess), userID)
+}
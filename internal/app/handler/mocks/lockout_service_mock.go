@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/lockout.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLockoutActions is a mock of LockoutActions interface.
+type MockLockoutActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutActionsMockRecorder
+}
+
+// MockLockoutActionsMockRecorder is the mock recorder for MockLockoutActions.
+type MockLockoutActionsMockRecorder struct {
+	mock *MockLockoutActions
+}
+
+// NewMockLockoutActions creates a new mock instance.
+func NewMockLockoutActions(ctrl *gomock.Controller) *MockLockoutActions {
+	mock := &MockLockoutActions{ctrl: ctrl}
+	mock.recorder = &MockLockoutActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockoutActions) EXPECT() *MockLockoutActionsMockRecorder {
+	return m.recorder
+}
+
+// UnlockAccount mocks base method.
+func (m *MockLockoutActions) UnlockAccount(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockAccount", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockAccount indicates an expected call of UnlockAccount.
+func (mr *MockLockoutActionsMockRecorder) UnlockAccount(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockAccount", reflect.TypeOf((*MockLockoutActions)(nil).UnlockAccount), userID)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/mfa.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMFAActions is a mock of MFAActions interface.
+type MockMFAActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAActionsMockRecorder
+}
+
+// MockMFAActionsMockRecorder is the mock recorder for MockMFAActions.
+type MockMFAActionsMockRecorder struct {
+	mock *MockMFAActions
+}
+
+// NewMockMFAActions creates a new mock instance.
+func NewMockMFAActions(ctrl *gomock.Controller) *MockMFAActions {
+	mock := &MockMFAActions{ctrl: ctrl}
+	mock.recorder = &MockMFAActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAActions) EXPECT() *MockMFAActionsMockRecorder {
+	return m.recorder
+}
+
+// BeginEnrollment mocks base method.
+func (m *MockMFAActions) BeginEnrollment(userID uint) (*model.MFAEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginEnrollment", userID)
+	ret0, _ := ret[0].(*model.MFAEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginEnrollment indicates an expected call of BeginEnrollment.
+func (mr *MockMFAActionsMockRecorder) BeginEnrollment(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginEnrollment", reflect.TypeOf((*MockMFAActions)(nil).BeginEnrollment), userID)
+}
+
+// ConfirmEnrollment mocks base method.
+func (m *MockMFAActions) ConfirmEnrollment(userID uint, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEnrollment", userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEnrollment indicates an expected call of ConfirmEnrollment.
+func (mr *MockMFAActionsMockRecorder) ConfirmEnrollment(userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEnrollment", reflect.TypeOf((*MockMFAActions)(nil).ConfirmEnrollment), userID, code)
+}
+
+// DisableMFA mocks base method.
+func (m *MockMFAActions) DisableMFA(userID uint, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockMFAActionsMockRecorder) DisableMFA(userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockMFAActions)(nil).DisableMFA), userID, code)
+}
+
+// MockMFAThrottle is a mock of MFAThrottle interface.
+type MockMFAThrottle struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAThrottleMockRecorder
+}
+
+// MockMFAThrottleMockRecorder is the mock recorder for MockMFAThrottle.
+type MockMFAThrottleMockRecorder struct {
+	mock *MockMFAThrottle
+}
+
+// NewMockMFAThrottle creates a new mock instance.
+func NewMockMFAThrottle(ctrl *gomock.Controller) *MockMFAThrottle {
+	mock := &MockMFAThrottle{ctrl: ctrl}
+	mock.recorder = &MockMFAThrottleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAThrottle) EXPECT() *MockMFAThrottleMockRecorder {
+	return m.recorder
+}
+
+// AllowMFA mocks base method.
+func (m *MockMFAThrottle) AllowMFA(userID uint, ip string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowMFA", userID, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllowMFA indicates an expected call of AllowMFA.
+func (mr *MockMFAThrottleMockRecorder) AllowMFA(userID, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowMFA", reflect.TypeOf((*MockMFAThrottle)(nil).AllowMFA), userID, ip)
+}
+
+// RecordMFAFailure mocks base method.
+func (m *MockMFAThrottle) RecordMFAFailure(userID uint, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFAFailure", userID, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMFAFailure indicates an expected call of RecordMFAFailure.
+func (mr *MockMFAThrottleMockRecorder) RecordMFAFailure(userID, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFAFailure", reflect.TypeOf((*MockMFAThrottle)(nil).RecordMFAFailure), userID, ip)
+}
+
+// RecordMFASuccess mocks base method.
+func (m *MockMFAThrottle) RecordMFASuccess(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFASuccess", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMFASuccess indicates an expected call of RecordMFASuccess.
+func (mr *MockMFAThrottleMockRecorder) RecordMFASuccess(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFASuccess", reflect.TypeOf((*MockMFAThrottle)(nil).RecordMFASuccess), userID)
+}
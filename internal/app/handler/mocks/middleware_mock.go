@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/middleware.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	service "golangHexagonal/internal/app/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAPIKeyAuthenticator is a mock of APIKeyAuthenticator interface.
+type MockAPIKeyAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyAuthenticatorMockRecorder
+}
+
+// MockAPIKeyAuthenticatorMockRecorder is the mock recorder for MockAPIKeyAuthenticator.
+type MockAPIKeyAuthenticatorMockRecorder struct {
+	mock *MockAPIKeyAuthenticator
+}
+
+// NewMockAPIKeyAuthenticator creates a new mock instance.
+func NewMockAPIKeyAuthenticator(ctrl *gomock.Controller) *MockAPIKeyAuthenticator {
+	mock := &MockAPIKeyAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyAuthenticator) EXPECT() *MockAPIKeyAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockAPIKeyAuthenticator) AuthenticateAPIKey(key string) (*service.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", key)
+	ret0, _ := ret[0].(*service.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockAPIKeyAuthenticatorMockRecorder) AuthenticateAPIKey(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockAPIKeyAuthenticator)(nil).AuthenticateAPIKey), key)
+}
+
+// MockSessionChecker is a mock of SessionChecker interface.
+type MockSessionChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionCheckerMockRecorder
+}
+
+// MockSessionCheckerMockRecorder is the mock recorder for MockSessionChecker.
+type MockSessionCheckerMockRecorder struct {
+	mock *MockSessionChecker
+}
+
+// NewMockSessionChecker creates a new mock instance.
+func NewMockSessionChecker(ctrl *gomock.Controller) *MockSessionChecker {
+	mock := &MockSessionChecker{ctrl: ctrl}
+	mock.recorder = &MockSessionCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionChecker) EXPECT() *MockSessionCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckSession mocks base method.
+func (m *MockSessionChecker) CheckSession(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSession", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSession indicates an expected call of CheckSession.
+func (mr *MockSessionCheckerMockRecorder) CheckSession(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSession", reflect.TypeOf((*MockSessionChecker)(nil).CheckSession), id)
+}
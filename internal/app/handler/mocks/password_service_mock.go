@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/password.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPasswordResetActions is a mock of PasswordResetActions interface.
+type MockPasswordResetActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetActionsMockRecorder
+}
+
+// MockPasswordResetActionsMockRecorder is the mock recorder for MockPasswordResetActions.
+type MockPasswordResetActionsMockRecorder struct {
+	mock *MockPasswordResetActions
+}
+
+// NewMockPasswordResetActions creates a new mock instance.
+func NewMockPasswordResetActions(ctrl *gomock.Controller) *MockPasswordResetActions {
+	mock := &MockPasswordResetActions{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetActions) EXPECT() *MockPasswordResetActionsMockRecorder {
+	return m.recorder
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockPasswordResetActions) RequestPasswordReset(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockPasswordResetActionsMockRecorder) RequestPasswordReset(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockPasswordResetActions)(nil).RequestPasswordReset), email)
+}
+
+// ResetPassword mocks base method.
+func (m *MockPasswordResetActions) ResetPassword(token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockPasswordResetActionsMockRecorder) ResetPassword(token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockPasswordResetActions)(nil).ResetPassword), token, password)
+}
+
+// MockPasswordChangeActions is a mock of PasswordChangeActions interface.
+type MockPasswordChangeActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordChangeActionsMockRecorder
+}
+
+// MockPasswordChangeActionsMockRecorder is the mock recorder for MockPasswordChangeActions.
+type MockPasswordChangeActionsMockRecorder struct {
+	mock *MockPasswordChangeActions
+}
+
+// NewMockPasswordChangeActions creates a new mock instance.
+func NewMockPasswordChangeActions(ctrl *gomock.Controller) *MockPasswordChangeActions {
+	mock := &MockPasswordChangeActions{ctrl: ctrl}
+	mock.recorder = &MockPasswordChangeActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordChangeActions) EXPECT() *MockPasswordChangeActionsMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockPasswordChangeActions) ChangePassword(userID uint, currentID string, input model.ChangePasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userID, currentID, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockPasswordChangeActionsMockRecorder) ChangePassword(userID, currentID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockPasswordChangeActions)(nil).ChangePassword), userID, currentID, input)
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/session.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSessionActions is a mock of SessionActions interface.
+type MockSessionActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionActionsMockRecorder
+}
+
+// MockSessionActionsMockRecorder is the mock recorder for MockSessionActions.
+type MockSessionActionsMockRecorder struct {
+	mock *MockSessionActions
+}
+
+// NewMockSessionActions creates a new mock instance.
+func NewMockSessionActions(ctrl *gomock.Controller) *MockSessionActions {
+	mock := &MockSessionActions{ctrl: ctrl}
+	mock.recorder = &MockSessionActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionActions) EXPECT() *MockSessionActionsMockRecorder {
+	return m.recorder
+}
+
+// ListSessions mocks base method.
+func (m *MockSessionActions) ListSessions(userID uint, currentID string) ([]*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", userID, currentID)
+	ret0, _ := ret[0].([]*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockSessionActionsMockRecorder) ListSessions(userID, currentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockSessionActions)(nil).ListSessions), userID, currentID)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockSessionActions) RevokeOtherSessions(userID uint, currentID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", userID, currentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockSessionActionsMockRecorder) RevokeOtherSessions(userID, currentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockSessionActions)(nil).RevokeOtherSessions), userID, currentID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockSessionActions) RevokeSession(userID uint, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockSessionActionsMockRecorder) RevokeSession(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockSessionActions)(nil).RevokeSession), userID, id)
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	model "golangHexagonal/internal/app/model"
+	service "golangHexagonal/internal/app/service"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -49,17 +50,17 @@ func (mr *MockUserActionsMockRecorder) CreateUser(user interface{}) *gomock.Call
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserActions) DeleteUser(id uint) error {
+func (m *MockUserActions) DeleteUser(actor service.Actor, id, version uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", id)
+	ret := m.ctrl.Call(m, "DeleteUser", actor, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserActionsMockRecorder) DeleteUser(id interface{}) *gomock.Call {
+func (mr *MockUserActionsMockRecorder) DeleteUser(actor, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserActions)(nil).DeleteUser), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserActions)(nil).DeleteUser), actor, id, version)
 }
 
 // GetUserByID mocks base method.
@@ -78,30 +79,75 @@ func (mr *MockUserActionsMockRecorder) GetUserByID(id interface{}) *gomock.Call
 }
 
 // GetUsers mocks base method.
-func (m *MockUserActions) GetUsers() ([]*model.User, error) {
+func (m *MockUserActions) GetUsers(query model.UserQuery) (*model.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsers")
-	ret0, _ := ret[0].([]*model.User)
+	ret := m.ctrl.Call(m, "GetUsers", query)
+	ret0, _ := ret[0].(*model.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUsers indicates an expected call of GetUsers.
-func (mr *MockUserActionsMockRecorder) GetUsers() *gomock.Call {
+func (mr *MockUserActionsMockRecorder) GetUsers(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserActions)(nil).GetUsers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserActions)(nil).GetUsers), query)
+}
+
+// GetUsersAfter mocks base method.
+func (m *MockUserActions) GetUsersAfter(query model.UserQuery, cursor string) (*model.UserCursorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersAfter", query, cursor)
+	ret0, _ := ret[0].(*model.UserCursorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersAfter indicates an expected call of GetUsersAfter.
+func (mr *MockUserActionsMockRecorder) GetUsersAfter(query, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersAfter", reflect.TypeOf((*MockUserActions)(nil).GetUsersAfter), query, cursor)
+}
+
+// PatchUser mocks base method.
+func (m *MockUserActions) PatchUser(actor service.Actor, id, version uint, patch model.UserPatch) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", actor, id, version, patch)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchUser indicates an expected call of PatchUser.
+func (mr *MockUserActionsMockRecorder) PatchUser(actor, id, version, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockUserActions)(nil).PatchUser), actor, id, version, patch)
+}
+
+// RestoreUser mocks base method.
+func (m *MockUserActions) RestoreUser(actor service.Actor, id uint) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", actor, id)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockUserActionsMockRecorder) RestoreUser(actor, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockUserActions)(nil).RestoreUser), actor, id)
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserActions) UpdateUser(user *model.User) error {
+func (m *MockUserActions) UpdateUser(actor service.Actor, user *model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret := m.ctrl.Call(m, "UpdateUser", actor, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserActionsMockRecorder) UpdateUser(user interface{}) *gomock.Call {
+func (mr *MockUserActionsMockRecorder) UpdateUser(actor, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserActions)(nil).UpdateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserActions)(nil).UpdateUser), actor, user)
 }
@@ -0,0 +1,62 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/handler/verification.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVerificationActions is a mock of VerificationActions interface.
+type MockVerificationActions struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerificationActionsMockRecorder
+}
+
+// MockVerificationActionsMockRecorder is the mock recorder for MockVerificationActions.
+type MockVerificationActionsMockRecorder struct {
+	mock *MockVerificationActions
+}
+
+// NewMockVerificationActions creates a new mock instance.
+func NewMockVerificationActions(ctrl *gomock.Controller) *MockVerificationActions {
+	mock := &MockVerificationActions{ctrl: ctrl}
+	mock.recorder = &MockVerificationActionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerificationActions) EXPECT() *MockVerificationActionsMockRecorder {
+	return m.recorder
+}
+
+// ResendVerification mocks base method.
+func (m *MockVerificationActions) ResendVerification(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockVerificationActionsMockRecorder) ResendVerification(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockVerificationActions)(nil).ResendVerification), email)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockVerificationActions) VerifyEmail(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockVerificationActionsMockRecorder) VerifyEmail(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockVerificationActions)(nil).VerifyEmail), token)
+}
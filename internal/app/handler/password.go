@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"golangHexagonal/internal/app/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+type PasswordResetActions interface {
+	RequestPasswordReset(email string) error
+	ResetPassword(token, password string) error
+}
+
+type PasswordChangeActions interface {
+	ChangePassword(userID uint, currentID string, input model.ChangePasswordInput) error
+}
+
+type PasswordHandler struct {
+	resetService  PasswordResetActions
+	changeService PasswordChangeActions
+}
+
+func NewPasswordHandler(resetService PasswordResetActions, changeService PasswordChangeActions) *PasswordHandler {
+	return &PasswordHandler{resetService: resetService, changeService: changeService}
+}
+
+// RegisterRoutes mounts the public reset flow and the change-password route
+// of the caller's own account. Reset requests are rate limited so that they
+// cannot be used to flood mailboxes, and password changes so that a stolen
+// session cannot be used to guess the current password.
+func (h *PasswordHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
+	app.Post("/password/forgot", limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 15 * time.Minute,
+		LimitReached: func(c *fiber.Ctx) error {
+			return problem(c, fiber.StatusTooManyRequests, "Too many requests")
+		},
+	}), h.ForgotPassword)
+	app.Post("/password/reset", h.ResetPassword)
+	app.Put("/me/password", auth, RequireSession(), limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 15 * time.Minute,
+		LimitReached: func(c *fiber.Ctx) error {
+			return problem(c, fiber.StatusTooManyRequests, "Too many requests")
+		},
+	}), h.ChangePassword)
+}
+
+// ForgotPassword answers the same way whether or not the email belongs to an
+// account.
+func (h *PasswordHandler) ForgotPassword(c *fiber.Ctx) error {
+	var input model.ForgotPasswordInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	if err := h.resetService.RequestPasswordReset(input.Email); err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not send reset email")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "If the account exists, a reset email has been sent"})
+}
+
+func (h *PasswordHandler) ResetPassword(c *fiber.Ctx) error {
+	var input model.ResetPasswordInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	if err := h.resetService.ResetPassword(input.Token, input.Password); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"message": "Password has been reset"})
+}
+
+// ChangePassword sets a new password for the caller and ends their other
+// sessions; the session making the request stays logged in.
+func (h *PasswordHandler) ChangePassword(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	var input model.ChangePasswordInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	if err := h.changeService.ChangePassword(claims.UserID, claims.SessionID, input); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
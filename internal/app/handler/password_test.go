@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+	servicemocks "golangHexagonal/internal/app/service/mocks"
+	"golangHexagonal/internal/config"
+	"golangHexagonal/internal/infrastructure/hashing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should return 202 when reset is requested", func(t *testing.T) {
+		mockResetActions := mocks.NewMockPasswordResetActions(ctrl)
+		mockResetActions.EXPECT().RequestPasswordReset("test@gmail.com").Return(nil)
+
+		body, err := json.Marshal(&model.ForgotPasswordInput{Email: "test@gmail.com"})
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/password/forgot", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		passwordHandler := NewPasswordHandler(mockResetActions, nil)
+		passwordHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 202, resp.StatusCode)
+	})
+
+	t.Run("should return 400 when request body is invalid", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/password/forgot", bytes.NewReader([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		passwordHandler := NewPasswordHandler(nil, nil)
+		passwordHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return 429 after too many requests", func(t *testing.T) {
+		mockResetActions := mocks.NewMockPasswordResetActions(ctrl)
+		mockResetActions.EXPECT().RequestPasswordReset("test@gmail.com").Return(nil).Times(5)
+
+		app := newTestApp()
+
+		passwordHandler := NewPasswordHandler(mockResetActions, nil)
+		passwordHandler.RegisterRoutes(app, noAuth)
+
+		body, err := json.Marshal(&model.ForgotPasswordInput{Email: "test@gmail.com"})
+		assert.Nil(t, err)
+
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest("POST", "/password/forgot", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			assert.Nil(t, err)
+			assert.Equal(t, 202, resp.StatusCode)
+		}
+
+		req := httptest.NewRequest("POST", "/password/forgot", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, 429, resp.StatusCode)
+	})
+}
+
+func TestHandler_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reset := func(t *testing.T, mockResetActions PasswordResetActions) int {
+		body, err := json.Marshal(&model.ResetPasswordInput{Token: "token", Password: "new password"})
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/password/reset", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		passwordHandler := NewPasswordHandler(mockResetActions, nil)
+		passwordHandler.RegisterRoutes(app, noAuth)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("should return 200 when password is reset", func(t *testing.T) {
+		mockResetActions := mocks.NewMockPasswordResetActions(ctrl)
+		mockResetActions.EXPECT().ResetPassword("token", "new password").Return(nil)
+
+		assert.Equal(t, 200, reset(t, mockResetActions))
+	})
+
+	t.Run("should return 422 when token is invalid", func(t *testing.T) {
+		mockResetActions := mocks.NewMockPasswordResetActions(ctrl)
+		mockResetActions.EXPECT().ResetPassword("token", "new password").Return(service.ErrInvalidResetToken)
+
+		assert.Equal(t, 422, reset(t, mockResetActions))
+	})
+
+	t.Run("should return 422 when the password breaks the policy", func(t *testing.T) {
+		mockResetActions := mocks.NewMockPasswordResetActions(ctrl)
+		mockResetActions.EXPECT().ResetPassword("token", "new password").Return(&service.ValidationError{
+			Violations: []service.FieldViolation{{Field: "password", Code: "breached", Message: "appears in a list of breached passwords"}},
+		})
+
+		assert.Equal(t, 422, reset(t, mockResetActions))
+	})
+
+	t.Run("should return 500 when service return error", func(t *testing.T) {
+		mockResetActions := mocks.NewMockPasswordResetActions(ctrl)
+		mockResetActions.EXPECT().ResetPassword("token", "new password").Return(errors.New("error"))
+
+		assert.Equal(t, 500, reset(t, mockResetActions))
+	})
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	input := model.ChangePasswordInput{CurrentPassword: "old password", NewPassword: "new password"}
+
+	change := func(t *testing.T, mockChangeActions PasswordChangeActions, claims *service.Claims) int {
+		body, err := json.Marshal(&input)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("PUT", "/me/password", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		passwordHandler := NewPasswordHandler(nil, mockChangeActions)
+		passwordHandler.RegisterRoutes(app, withClaims(claims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp.StatusCode
+	}
+
+	sessionClaims := &service.Claims{UserID: 2, Role: model.RoleUser, SessionID: "session"}
+
+	t.Run("should return 204 when password is changed", func(t *testing.T) {
+		mockChangeActions := mocks.NewMockPasswordChangeActions(ctrl)
+		mockChangeActions.EXPECT().ChangePassword(uint(2), "session", input).Return(nil)
+
+		assert.Equal(t, 204, change(t, mockChangeActions, sessionClaims))
+	})
+
+	t.Run("should return 422 when the current password is wrong", func(t *testing.T) {
+		mockChangeActions := mocks.NewMockPasswordChangeActions(ctrl)
+		mockChangeActions.EXPECT().ChangePassword(uint(2), "session", input).Return(&service.ValidationError{
+			Violations: []service.FieldViolation{{Field: "current_password", Code: "incorrect", Message: "is incorrect"}},
+		})
+
+		assert.Equal(t, 422, change(t, mockChangeActions, sessionClaims))
+	})
+
+	t.Run("should point policy violations at new_password", func(t *testing.T) {
+		hasher := hashing.NewHasher(hashing.NewBcrypt(4))
+		hash, err := hasher.Hash("old password")
+		assert.Nil(t, err)
+
+		mockRepo := servicemocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(2)).Return(&model.User{ID: 2, Password: hash}, nil)
+		changeService := service.NewPasswordChangeService(mockRepo, hasher, service.NewPasswordPolicy(config.PasswordConfig{}, nil), nil)
+
+		body, err := json.Marshal(&model.ChangePasswordInput{CurrentPassword: "old password", NewPassword: "short"})
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("PUT", "/me/password", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+		NewPasswordHandler(nil, changeService).RegisterRoutes(app, withClaims(sessionClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, 422, resp.StatusCode)
+
+		var respBody Problem
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, "new_password", respBody.Violations[0].Field)
+	})
+
+	t.Run("should return 403 for an API key", func(t *testing.T) {
+		keyClaims := &service.Claims{UserID: 2, Role: model.RoleUser, APIKeyID: 7, Scopes: []string{model.ScopeUsersWrite}}
+
+		assert.Equal(t, 403, change(t, nil, keyClaims))
+	})
+}
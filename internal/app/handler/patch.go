@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golangHexagonal/internal/app/service"
+	"golangHexagonal/internal/app/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEMergePatchJSON is the content type of RFC 7396 JSON merge patches.
+const MIMEMergePatchJSON = "application/merge-patch+json"
+
+// HeaderAcceptPatch lists the patch formats a resource accepts (RFC 5789).
+const HeaderAcceptPatch = "Accept-Patch"
+
+// parseStrictBody is parseBody for a JSON object that may only hold the
+// fields of input. Any other member, such as an id or a password next to a
+// profile, is a violation instead of being silently dropped.
+func parseStrictBody(c *fiber.Ctx, input any) error {
+	members, err := jsonObject(c.Body())
+	if err != nil {
+		return err
+	}
+	if violations := unknownMembers(members, input); len(violations) > 0 {
+		return &service.ValidationError{Violations: violations}
+	}
+
+	if err := json.Unmarshal(c.Body(), input); err != nil {
+		return err
+	}
+	return validation.Struct(input)
+}
+
+// parseMergePatch decodes an RFC 7396 merge patch into patch, whose pointer
+// fields stay nil for members the patch leaves out. Patches may not remove
+// members with null, since every patchable field is required.
+func parseMergePatch(c *fiber.Ctx, patch any) error {
+	members, err := jsonObject(c.Body())
+	if err != nil {
+		return err
+	}
+
+	violations := unknownMembers(members, patch)
+	for _, name := range sortedKeys(members) {
+		if bytes.Equal(members[name], []byte("null")) {
+			violations = append(violations, service.FieldViolation{Field: name, Code: "required", Message: "cannot be removed"})
+		}
+	}
+	if len(violations) > 0 {
+		return &service.ValidationError{Violations: violations}
+	}
+
+	if err := json.Unmarshal(c.Body(), patch); err != nil {
+		return err
+	}
+	return validation.Struct(patch)
+}
+
+// isMergePatch reports whether the request body is a merge patch. Plain
+// JSON is accepted as one as well.
+func isMergePatch(c *fiber.Ctx) bool {
+	mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	return err == nil && (mediaType == MIMEMergePatchJSON || mediaType == fiber.MIMEApplicationJSON)
+}
+
+// jsonObject splits a JSON object into its members.
+func jsonObject(body []byte) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		return nil, err
+	}
+	if members == nil {
+		return nil, &json.UnmarshalTypeError{Value: "null", Type: reflect.TypeOf(members)}
+	}
+	return members, nil
+}
+
+// unknownMembers lists the members that do not match a JSON field name of
+// input exactly.
+func unknownMembers(members map[string]json.RawMessage, input any) []service.FieldViolation {
+	typ := reflect.Indirect(reflect.ValueOf(input)).Type()
+	known := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		known = append(known, name)
+	}
+
+	var violations []service.FieldViolation
+	for _, name := range sortedKeys(members) {
+		if !slices.Contains(known, name) {
+			violations = append(violations, service.FieldViolation{Field: name, Code: "unknown", Message: "cannot be set"})
+		}
+	}
+	return violations
+}
+
+func sortedKeys(members map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"golangHexagonal/internal/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// MIMEProblemJSON is the content type of RFC 7807 problem details.
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem types other than the default "about:blank", which means the
+// status code says it all.
+const (
+	ProblemTypeDefault    = "about:blank"
+	ProblemTypeValidation = "/problems/validation"
+)
+
+// RequestIDMiddleware gives every request an ID, returned in the X-Request-ID
+// header and in problem responses, so that clients can quote it in reports.
+func RequestIDMiddleware() fiber.Handler {
+	return requestid.New()
+}
+
+// Problem is an RFC 7807 problem details object. RequestID and Violations
+// are extension members.
+type Problem struct {
+	Type       string                   `json:"type"`
+	Title      string                   `json:"title"`
+	Status     int                      `json:"status"`
+	Detail     string                   `json:"detail,omitempty"`
+	Instance   string                   `json:"instance,omitempty"`
+	RequestID  string                   `json:"request_id,omitempty"`
+	Violations []service.FieldViolation `json:"violations,omitempty"`
+}
+
+// newProblem describes a failure of the current request. The title is the
+// reason phrase of status.
+func newProblem(c *fiber.Ctx, status int, detail string) *Problem {
+	requestID, _ := c.Locals("requestid").(string)
+	return &Problem{
+		Type:      ProblemTypeDefault,
+		Title:     utils.StatusMessage(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Path(),
+		RequestID: requestID,
+	}
+}
+
+// writeProblem sends p as application/problem+json.
+func writeProblem(c *fiber.Ctx, p *Problem) error {
+	return c.Status(p.Status).JSON(p, MIMEProblemJSON)
+}
+
+// problem answers the request with a problem of status and detail. It is
+// how every handler reports an error.
+func problem(c *fiber.Ctx, status int, detail string) error {
+	return writeProblem(c, newProblem(c, status, detail))
+}
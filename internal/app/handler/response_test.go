@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// serializedFields returns the JSON names of every field encoding/json
+// writes for typ, following nested structs, slices and pointers.
+func serializedFields(typ reflect.Type) []string {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return serializedFields(typ.Elem())
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+		names = append(names, serializedFields(field.Type)...)
+	}
+	return names
+}
+
+func TestHandler_ResponsesNeverContainPasswords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, value := range []any{
+		model.User{},
+		model.UserResponse{},
+		model.UserListResponse{},
+		model.UserCursorListResponse{},
+		model.Session{},
+		model.APIKey{},
+	} {
+		typ := reflect.TypeOf(value)
+		t.Run("should not serialize a password in "+typ.Name(), func(t *testing.T) {
+			for _, name := range serializedFields(typ) {
+				lower := strings.ToLower(name)
+				assert.NotContains(t, lower, "password")
+				assert.NotContains(t, lower, "hash")
+			}
+		})
+	}
+
+	t.Run("should map a cursor page without secrets", func(t *testing.T) {
+		user := &model.User{ID: 1, Name: "test", Email: "test@gmail.com", Password: "hash", MFASecret: "SECRET", MFALastStep: 7, Version: 3}
+
+		for _, nextCursor := range []string{"next.sig", ""} {
+			body, err := json.Marshal(model.NewUserCursorListResponse(&model.UserCursorPage{Users: []*model.User{user}, PerPage: 20, NextCursor: nextCursor}))
+			assert.Nil(t, err)
+
+			var response map[string]any
+			assert.Nil(t, json.Unmarshal(body, &response))
+			if nextCursor != "" {
+				assert.Equal(t, nextCursor, response["next_cursor"])
+			} else {
+				assert.NotContains(t, response, "next_cursor")
+			}
+
+			users := response["users"].([]any)
+			assert.Len(t, users, 1)
+			for _, key := range []string{"password", "mfa_secret", "MFASecret", "mfa_last_step", "MFALastStep", "version", "Version"} {
+				assert.NotContains(t, users[0], key)
+			}
+			assert.NotContains(t, string(body), "SECRET")
+			assert.NotContains(t, string(body), "hash")
+		}
+	})
+
+	t.Run("should not return the stored hash from any user route", func(t *testing.T) {
+		const hash = "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA"
+		user := func() *model.User {
+			return &model.User{ID: 1, Name: "test", Email: "test@gmail.com", Password: hash}
+		}
+
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(created *model.User) error {
+			created.Password = hash
+			return nil
+		})
+		mockUserService.EXPECT().GetUserByID(uint(1)).Return(user(), nil)
+		mockUserService.EXPECT().UpdateUser(adminActor, gomock.Any()).DoAndReturn(func(_ any, updated *model.User) error {
+			updated.Password = hash
+			return nil
+		})
+		mockUserService.EXPECT().GetUsers(gomock.Any()).Return(&model.UserPage{Users: []*model.User{user()}, Total: 1, Page: 1, PerPage: 20}, nil)
+
+		app := newTestApp()
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		for _, tc := range []struct{ method, path, body string }{
+			{"POST", "/users", `{"name":"test","email":"test@gmail.com","password":"secret password"}`},
+			{"GET", "/users/1", ""},
+			{"PUT", "/users/1", `{"name":"test","email":"test@gmail.com"}`},
+			{"GET", "/users", ""},
+		} {
+			req := httptest.NewRequest(tc.method, tc.path, bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"1"`)
+
+			resp, err := app.Test(req)
+			assert.Nil(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.Nil(t, err)
+			assert.Less(t, resp.StatusCode, 300, tc.method+" "+tc.path)
+			assert.NotContains(t, string(body), hash, tc.method+" "+tc.path)
+			assert.NotContains(t, strings.ToLower(string(body)), "password", tc.method+" "+tc.path)
+			assert.True(t, json.Valid(body))
+		}
+	})
+}
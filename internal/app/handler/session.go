@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"golangHexagonal/internal/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionActions interface {
+	ListSessions(userID uint, currentID string) ([]*model.Session, error)
+	RevokeSession(userID uint, id string) error
+	RevokeOtherSessions(userID uint, currentID string) error
+}
+
+type SessionHandler struct {
+	sessionService SessionActions
+}
+
+func NewSessionHandler(sessionService SessionActions) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// RegisterRoutes mounts the session routes of the caller's own account.
+func (h *SessionHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
+	sessions := app.Group("/me/sessions", auth, RequireSession())
+	sessions.Get("/", h.ListSessions)
+	sessions.Delete("/", h.RevokeOtherSessions)
+	sessions.Delete("/:id", h.RevokeSession)
+}
+
+func (h *SessionHandler) ListSessions(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	sessions, err := h.sessionService.ListSessions(claims.UserID, claims.SessionID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"sessions": sessions})
+}
+
+func (h *SessionHandler) RevokeSession(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	if err := h.sessionService.RevokeSession(claims.UserID, c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeOtherSessions logs the user out everywhere except on the device
+// making the request.
+func (h *SessionHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		return problem(c, fiber.StatusUnauthorized, "Missing or malformed token")
+	}
+
+	if err := h.sessionService.RevokeOtherSessions(claims.UserID, claims.SessionID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
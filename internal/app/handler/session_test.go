@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Sessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionClaims := &service.Claims{UserID: 2, Role: model.RoleUser, SessionID: "current"}
+
+	request := func(t *testing.T, mockSessionActions SessionActions, method, path string) *http.Response {
+		app := newTestApp()
+
+		sessionHandler := NewSessionHandler(mockSessionActions)
+		sessionHandler.RegisterRoutes(app, withClaims(sessionClaims))
+
+		resp, err := app.Test(httptest.NewRequest(method, path, nil))
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("should list the caller's sessions", func(t *testing.T) {
+		mockSessionActions := mocks.NewMockSessionActions(ctrl)
+		mockSessionActions.EXPECT().ListSessions(uint(2), "current").Return([]*model.Session{
+			{ID: "current", UserAgent: "firefox", Current: true},
+		}, nil)
+
+		resp := request(t, mockSessionActions, "GET", "/me/sessions")
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody struct {
+			Sessions []map[string]interface{} `json:"sessions"`
+		}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Len(t, respBody.Sessions, 1)
+		assert.Equal(t, "firefox", respBody.Sessions[0]["user_agent"])
+		assert.Equal(t, true, respBody.Sessions[0]["current"])
+	})
+
+	t.Run("should revoke one session", func(t *testing.T) {
+		mockSessionActions := mocks.NewMockSessionActions(ctrl)
+		mockSessionActions.EXPECT().RevokeSession(uint(2), "laptop").Return(nil)
+
+		resp := request(t, mockSessionActions, "DELETE", "/me/sessions/laptop")
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+
+	t.Run("should return 404 for an unknown session", func(t *testing.T) {
+		mockSessionActions := mocks.NewMockSessionActions(ctrl)
+		mockSessionActions.EXPECT().RevokeSession(uint(2), "unknown").Return(service.ErrSessionNotFound)
+
+		resp := request(t, mockSessionActions, "DELETE", "/me/sessions/unknown")
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("should revoke every other session", func(t *testing.T) {
+		mockSessionActions := mocks.NewMockSessionActions(ctrl)
+		mockSessionActions.EXPECT().RevokeOtherSessions(uint(2), "current").Return(nil)
+
+		resp := request(t, mockSessionActions, "DELETE", "/me/sessions")
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+}
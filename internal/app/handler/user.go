@@ -2,104 +2,245 @@ package handler
 
 import (
 	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 )
 
 type UserActions interface {
 	CreateUser(user *model.User) error
 	GetUserByID(id uint) (*model.User, error)
-	UpdateUser(user *model.User) error
-	DeleteUser(id uint) error
-	GetUsers() ([]*model.User, error)
+	UpdateUser(actor service.Actor, user *model.User) error
+	PatchUser(actor service.Actor, id, version uint, patch model.UserPatch) (*model.User, error)
+	DeleteUser(actor service.Actor, id, version uint) error
+	RestoreUser(actor service.Actor, id uint) (*model.User, error)
+	GetUsers(query model.UserQuery) (*model.UserPage, error)
+	GetUsersAfter(query model.UserQuery, cursor string) (*model.UserCursorPage, error)
+}
+
+// UserRouteRoles lists the roles allowed on each protected user route. An
+// empty list lets any authenticated caller through.
+type UserRouteRoles struct {
+	Get     []string
+	Update  []string
+	Delete  []string
+	Restore []string
+	List    []string
+}
+
+// DefaultUserRouteRoles restricts listing, deleting and restoring users to
+// admins.
+func DefaultUserRouteRoles() UserRouteRoles {
+	return UserRouteRoles{
+		Delete:  []string{model.RoleAdmin},
+		Restore: []string{model.RoleAdmin},
+		List:    []string{model.RoleAdmin},
+	}
 }
 
 type UserHandler struct {
 	service UserActions
+	roles   UserRouteRoles
 }
 
-func NewUserHandler(service UserActions) *UserHandler {
-	return &UserHandler{service: service}
+func NewUserHandler(service UserActions, roles UserRouteRoles) *UserHandler {
+	return &UserHandler{service: service, roles: roles}
 }
 
-func (h *UserHandler) RegisterRoutes(app *fiber.App) {
+// RegisterRoutes mounts the user routes. Sign-up stays public; every other
+// route goes through the auth middleware of the /users group and, for API
+// keys, needs the matching users scope. Single users carry an ETag, which
+// PUT, PATCH and DELETE must send back in If-Match.
+func (h *UserHandler) RegisterRoutes(app *fiber.App, auth fiber.Handler) {
 	app.Post("/users", h.CreateUser)
-	app.Get("/users/:id", h.GetUser)
-	app.Put("/users/:id", h.UpdateUser)
-	app.Delete("/users/:id", h.DeleteUser)
-	app.Get("/users", h.GetUsers)
+
+	users := app.Group("/users", auth)
+	users.Get("/:id", RequireScope(model.ScopeUsersRead), RequireRole(h.roles.Get...), h.GetUser)
+	users.Put("/:id", RequireScope(model.ScopeUsersWrite), RequireRole(h.roles.Update...), h.UpdateUser)
+	users.Patch("/:id", RequireScope(model.ScopeUsersWrite), RequireRole(h.roles.Update...), h.PatchUser)
+	users.Delete("/:id", RequireScope(model.ScopeUsersWrite), RequireRole(h.roles.Delete...), h.DeleteUser)
+	users.Post("/:id/restore", RequireScope(model.ScopeUsersWrite), RequireRole(h.roles.Restore...), h.RestoreUser)
+	users.Get("/", RequireScope(model.ScopeUsersRead), RequireRole(h.roles.List...), h.GetUsers)
 }
 
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 
-	user := new(model.User)
-	if err := c.BodyParser(user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	var input model.CreateUserInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
 	}
 
-	err := h.service.CreateUser(user)
-
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	user := input.ToUser()
+	if err := h.service.CreateUser(user); err != nil {
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(user)
+	return c.Status(fiber.StatusCreated).JSON(model.NewUserResponse(user))
 
 }
 
 func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ID"})
+		return problem(c, fiber.StatusBadRequest, "Invalid ID")
 	}
 
 	user, err := h.service.GetUserByID(uint(id))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
-	return c.JSON(user)
+	setUserETag(c, user)
+	return c.JSON(model.NewUserResponse(user))
 }
 
+// UpdateUser replaces the profile of a user. The body must hold every field
+// of model.UpdateUserInput and nothing else.
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return problem(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	var input model.UpdateUserInput
+	if err := parseStrictBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	user := input.ToUser(uint(id), version)
+
+	if err := h.service.UpdateUser(ActorFromContext(c), user); err != nil {
+		return err
+	}
+
+	setUserETag(c, user)
+	return c.JSON(model.NewUserResponse(user))
+
+}
+
+// PatchUser changes the profile fields present in a JSON merge patch.
+func (h *UserHandler) PatchUser(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return problem(c, fiber.StatusBadRequest, "Invalid ID")
 	}
 
-	user := new(model.User)
-	if err := c.BodyParser(user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
 	}
 
-	user.ID = uint(id)
+	if !isMergePatch(c) {
+		c.Set(HeaderAcceptPatch, MIMEMergePatchJSON)
+		return problem(c, fiber.StatusUnsupportedMediaType, "Send a JSON merge patch as "+MIMEMergePatchJSON)
+	}
 
-	if err := h.service.UpdateUser(user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	var patch model.UserPatch
+	if err := parseMergePatch(c, &patch); err != nil {
+		return bodyError(c, err)
 	}
 
-	return c.JSON(user)
+	user, err := h.service.PatchUser(ActorFromContext(c), uint(id), version, patch)
+	if err != nil {
+		return err
+	}
 
+	setUserETag(c, user)
+	return c.JSON(model.NewUserResponse(user))
 }
 
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ID"})
+		return problem(c, fiber.StatusBadRequest, "Invalid ID")
 	}
 
-	if err := h.service.DeleteUser(uint(id)); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteUser(ActorFromContext(c), uint(id), version); err != nil {
+		return err
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// RestoreUser undoes the deletion of a user that has not been purged yet.
+func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return problem(c, fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	user, err := h.service.RestoreUser(ActorFromContext(c), uint(id))
+	if err != nil {
+		return err
+	}
+
+	setUserETag(c, user)
+	return c.JSON(model.NewUserResponse(user))
+}
+
+// GetUsers lists one page of users. See model.ListUsersInput for the query
+// parameters.
 func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
-	users, err := h.service.GetUsers()
+	var input model.ListUsersInput
+	if err := parseQuery(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	if input.Paginate == "offset" && input.Cursor != "" {
+		return problem(c, fiber.StatusBadRequest, "cursor cannot be combined with paginate=offset")
+	}
+	if input.UsesCursor() {
+		return h.getUsersAfter(c, input)
+	}
+
+	page, err := h.service.GetUsers(input.ToQuery())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
+	}
+
+	response := model.NewUserListResponse(page)
+	if page.HasNext() {
+		response.Links.Next = listLink(c, "page", strconv.Itoa(page.Page+1))
 	}
+	if page.Page > 1 {
+		response.Links.Prev = listLink(c, "page", strconv.Itoa(page.Page-1))
+	}
+	return c.JSON(response)
+}
+
+// getUsersAfter is GetUsers in cursor mode. Keyset pages only link forward.
+func (h *UserHandler) getUsersAfter(c *fiber.Ctx, input model.ListUsersInput) error {
+	page, err := h.service.GetUsersAfter(input.ToQuery(), input.Cursor)
+	if err != nil {
+		return err
+	}
+
+	response := model.NewUserCursorListResponse(page)
+	if page.NextCursor != "" {
+		response.Links.Next = listLink(c, "cursor", page.NextCursor)
+	}
+	return c.JSON(response)
+}
+
+// listLink returns the URL of the current request with the query parameter
+// key set to value.
+func listLink(c *fiber.Ctx, key, value string) string {
+	args := fasthttp.AcquireArgs()
+	defer fasthttp.ReleaseArgs(args)
 
-	return c.JSON(fiber.Map{"users": users, "total": len(users), "message": "success", "status": 200, "success": true})
+	c.Request().URI().QueryArgs().CopyTo(args)
+	args.Set(key, value)
+	return c.Path() + "?" + args.String()
 }
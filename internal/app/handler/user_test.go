@@ -7,8 +7,12 @@ import (
 
 	"golangHexagonal/internal/app/handler/mocks"
 	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
@@ -20,7 +24,7 @@ func TestHandler_CreateUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	t.Run("should return 201 when create user success", func(t *testing.T) {
-		reqBody := model.User{
+		reqBody := model.CreateUserInput{
 			Name:     "test",
 			Email:    "test@gmail.com",
 			Password: "123456",
@@ -30,15 +34,15 @@ func TestHandler_CreateUser(t *testing.T) {
 		assert.Nil(t, err)
 
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().CreateUser(&reqBody).Return(nil)
+		mockUserService.EXPECT().CreateUser(reqBody.ToUser()).Return(nil)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -50,10 +54,10 @@ func TestHandler_CreateUser(t *testing.T) {
 		req := httptest.NewRequest("POST", "/users", bytes.NewReader([]byte("invalid json")))
 		req.Header.Set("Content-Type", "application/json")
 
-		app := fiber.New()
+		app := newTestApp()
 
-		userHandler := NewUserHandler(nil)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(nil, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -61,8 +65,69 @@ func TestHandler_CreateUser(t *testing.T) {
 		assert.Equal(t, 400, resp.StatusCode)
 	})
 
+	t.Run("should return 422 listing every invalid field before calling the service", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/users", bytes.NewReader([]byte(`{"name":"","email":"not an email"}`)))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		userHandler := NewUserHandler(mocks.NewMockUserActions(ctrl), DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 422, resp.StatusCode)
+
+		var response struct {
+			Violations []service.FieldViolation `json:"violations"`
+		}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&response))
+
+		fields := map[string]string{}
+		for _, violation := range response.Violations {
+			fields[violation.Field] = violation.Code
+		}
+		assert.Equal(t, map[string]string{"name": "required", "email": "email", "password": "required"}, fields)
+	})
+
+	t.Run("should return 422 with the violations when the password breaks the policy", func(t *testing.T) {
+		reqBody := model.CreateUserInput{
+			Name:     "test",
+			Email:    "test@gmail.com",
+			Password: "123456",
+		}
+
+		body, err := json.Marshal(reqBody)
+		assert.Nil(t, err)
+
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().CreateUser(reqBody.ToUser()).Return(&service.ValidationError{
+			Violations: []service.FieldViolation{{Field: "password", Code: "too_short", Message: "must be at least 10 characters long"}},
+		})
+
+		app := newTestApp()
+
+		req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 422, resp.StatusCode)
+
+		var response struct {
+			Violations []service.FieldViolation `json:"violations"`
+		}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "too_short", response.Violations[0].Code)
+	})
+
 	t.Run("should return 500 when service return error", func(t *testing.T) {
-		reqBody := model.User{
+		reqBody := model.CreateUserInput{
 			Name:     "test",
 			Email:    "test@gmail.com",
 			Password: "123456",
@@ -72,15 +137,15 @@ func TestHandler_CreateUser(t *testing.T) {
 		assert.Nil(t, err)
 
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().CreateUser(&reqBody).Return(errors.New("error"))
+		mockUserService.EXPECT().CreateUser(reqBody.ToUser()).Return(errors.New("error"))
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -94,46 +159,49 @@ func TestHandler_GetUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	t.Run("should return 200 when get user success", func(t *testing.T) {
-		expectedRespBody := model.User{
+		user := &model.User{
 			ID:       1,
 			Name:     "test",
 			Email:    "test@gmail.com",
-			Password: "123456",
+			Password: "hashed password",
+			Role:     model.RoleUser,
+			Version:  7,
 		}
 
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().GetUserByID(uint(1)).Return(&expectedRespBody, nil)
+		mockUserService.EXPECT().GetUserByID(uint(1)).Return(user, nil)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("GET", "/users/1", nil)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
 
 		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, `"7"`, resp.Header.Get("ETag"))
 
-		var respBody model.User
+		var respBody model.UserResponse
 		err = json.NewDecoder(resp.Body).Decode(&respBody)
 		assert.Nil(t, err)
 
-		assert.Equal(t, expectedRespBody, respBody)
+		assert.Equal(t, model.UserResponse{ID: 1, Name: "test", Email: "test@gmail.com", Role: model.RoleUser}, respBody)
 	})
 
 	t.Run("should return 400 when id is invalid", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("GET", "/users/invalid", nil)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -145,13 +213,13 @@ func TestHandler_GetUser(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
 		mockUserService.EXPECT().GetUserByID(uint(1)).Return(nil, errors.New("error"))
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("GET", "/users/1", nil)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -165,40 +233,38 @@ func TestHandler_UpdateUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	t.Run("should return 200 when update user success", func(t *testing.T) {
-		reqBody := model.User{
-			ID:       1,
-			Name:     "update",
-			Email:    "update@gmail.com",
-			Password: "123456",
+		reqBody := model.UpdateUserInput{
+			Name:  "update",
+			Email: "update@gmail.com",
 		}
 
-		expectedRespBody := model.User{
-			ID:       1,
-			Name:     "update",
-			Email:    "update@gmail.com",
-			Password: "123456",
+		expectedRespBody := model.UserResponse{
+			ID:    1,
+			Name:  "update",
+			Email: "update@gmail.com",
 		}
 
 		body, err := json.Marshal(reqBody)
 		assert.Nil(t, err)
 
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().UpdateUser(&reqBody).Return(nil)
+		mockUserService.EXPECT().UpdateUser(adminActor, reqBody.ToUser(1, 1)).Return(nil)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("PUT", "/users/1", bytes.NewReader(body))
+		req.Header.Set("If-Match", `"1"`)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
 
 		assert.Equal(t, 200, resp.StatusCode)
 
-		var respBody model.User
+		var respBody model.UserResponse
 		err = json.NewDecoder(resp.Body).Decode(&respBody)
 		assert.Nil(t, err)
 
@@ -207,12 +273,13 @@ func TestHandler_UpdateUser(t *testing.T) {
 
 	t.Run("should return 400 when request body is invalid", func(t *testing.T) {
 		req := httptest.NewRequest("PUT", "/users/1", bytes.NewReader([]byte("invalid json")))
+		req.Header.Set("If-Match", `"1"`)
 		req.Header.Set("Content-Type", "application/json")
 
-		app := fiber.New()
+		app := newTestApp()
 
-		userHandler := NewUserHandler(nil)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(nil, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -220,16 +287,55 @@ func TestHandler_UpdateUser(t *testing.T) {
 		assert.Equal(t, 400, resp.StatusCode)
 	})
 
+	t.Run("should return 422 when the body sets the id or password", func(t *testing.T) {
+		body := []byte(`{"id":2,"name":"update","email":"update@gmail.com","password":"secret"}`)
+		req := httptest.NewRequest("PUT", "/users/1", bytes.NewReader(body))
+		req.Header.Set("If-Match", `"1"`)
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		userHandler := NewUserHandler(nil, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 422, resp.StatusCode)
+		var respBody Problem
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, []service.FieldViolation{
+			{Field: "id", Code: "unknown", Message: "cannot be set"},
+			{Field: "password", Code: "unknown", Message: "cannot be set"},
+		}, respBody.Violations)
+	})
+
+	t.Run("should return 422 when a field is missing", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/users/1", bytes.NewReader([]byte(`{"name":"update"}`)))
+		req.Header.Set("If-Match", `"1"`)
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		userHandler := NewUserHandler(nil, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 422, resp.StatusCode)
+	})
+
 	t.Run("should return 400 when id is invalid", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("PUT", "/users/invalid", nil)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 		assert.Nil(t, err)
@@ -238,26 +344,25 @@ func TestHandler_UpdateUser(t *testing.T) {
 	})
 
 	t.Run("should return 500 when service return error", func(t *testing.T) {
-		reqBody := model.User{
-			ID:       1,
-			Name:     "update",
-			Email:    "update@gmail.com",
-			Password: "123456",
+		reqBody := model.UpdateUserInput{
+			Name:  "update",
+			Email: "update@gmail.com",
 		}
 
 		body, err := json.Marshal(reqBody)
 		assert.Nil(t, err)
 
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().UpdateUser(&reqBody).Return(errors.New("error"))
+		mockUserService.EXPECT().UpdateUser(adminActor, reqBody.ToUser(1, 1)).Return(errors.New("error"))
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("PUT", "/users/1", bytes.NewReader(body))
+		req.Header.Set("If-Match", `"1"`)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 
@@ -266,21 +371,154 @@ func TestHandler_UpdateUser(t *testing.T) {
 	})
 }
 
+func TestHandler_UserPreconditions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	send := func(t *testing.T, actions UserActions, method, ifMatch string) *http.Response {
+		body := `{"name":"update","email":"update@gmail.com"}`
+		req := httptest.NewRequest(method, "/users/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		app := newTestApp()
+		userHandler := NewUserHandler(actions, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	for _, method := range []string{"PUT", "PATCH", "DELETE"} {
+		t.Run("should return 428 when "+method+" has no If-Match", func(t *testing.T) {
+			resp := send(t, nil, method, "")
+			assert.Equal(t, 428, resp.StatusCode)
+		})
+
+		t.Run("should return 428 when "+method+" has If-Match *", func(t *testing.T) {
+			resp := send(t, nil, method, "*")
+			assert.Equal(t, 428, resp.StatusCode)
+		})
+
+		t.Run("should return 412 when "+method+" has an If-Match that cannot match", func(t *testing.T) {
+			for _, ifMatch := range []string{`W/"1"`, `"1", "2"`, "1"} {
+				resp := send(t, nil, method, ifMatch)
+				assert.Equal(t, 412, resp.StatusCode, ifMatch)
+			}
+		})
+	}
+
+	t.Run("should return 412 when the user was modified", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().DeleteUser(adminActor, uint(1), uint(3)).Return(model.ErrUserModified)
+
+		resp := send(t, mockUserService, "DELETE", `"3"`)
+		assert.Equal(t, 412, resp.StatusCode)
+	})
+
+	t.Run("should return the ETag of the new version", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().UpdateUser(adminActor, gomock.Any()).DoAndReturn(func(_ service.Actor, user *model.User) error {
+			assert.Equal(t, uint(3), user.Version)
+			user.Version++
+			return nil
+		})
+
+		resp := send(t, mockUserService, "PUT", `"3"`)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, `"4"`, resp.Header.Get("ETag"))
+	})
+}
+
+func TestHandler_PatchUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	patch := func(t *testing.T, actions UserActions, contentType, body string) *http.Response {
+		req := httptest.NewRequest("PATCH", "/users/1", strings.NewReader(body))
+		req.Header.Set("If-Match", `"1"`)
+		req.Header.Set("Content-Type", contentType)
+
+		app := newTestApp()
+		userHandler := NewUserHandler(actions, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("should pass only the fields in the patch", func(t *testing.T) {
+		name := "update"
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().PatchUser(adminActor, uint(1), uint(1), model.UserPatch{Name: &name}).
+			Return(&model.User{ID: 1, Name: "update", Email: "user@gmail.com"}, nil)
+
+		resp := patch(t, mockUserService, MIMEMergePatchJSON, `{"name":"update"}`)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody model.UserResponse
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, model.UserResponse{ID: 1, Name: "update", Email: "user@gmail.com"}, respBody)
+	})
+
+	t.Run("should return 422 when the patch removes or sets a protected field", func(t *testing.T) {
+		resp := patch(t, nil, MIMEMergePatchJSON, `{"email":null,"password":"secret"}`)
+		assert.Equal(t, 422, resp.StatusCode)
+
+		var respBody Problem
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, []service.FieldViolation{
+			{Field: "password", Code: "unknown", Message: "cannot be set"},
+			{Field: "email", Code: "required", Message: "cannot be removed"},
+		}, respBody.Violations)
+	})
+
+	t.Run("should return 422 when a patched field is invalid", func(t *testing.T) {
+		resp := patch(t, nil, MIMEMergePatchJSON, `{"email":"not an email"}`)
+		assert.Equal(t, 422, resp.StatusCode)
+	})
+
+	t.Run("should return 400 when the patch is not an object", func(t *testing.T) {
+		resp := patch(t, nil, MIMEMergePatchJSON, `["name"]`)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return 415 for other patch formats", func(t *testing.T) {
+		resp := patch(t, nil, "application/json-patch+json", `[{"op":"replace","path":"/name","value":"x"}]`)
+		assert.Equal(t, 415, resp.StatusCode)
+		assert.Equal(t, MIMEMergePatchJSON, resp.Header.Get(HeaderAcceptPatch))
+	})
+
+	t.Run("should return the service error", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().PatchUser(adminActor, uint(1), uint(1), gomock.Any()).
+			Return(nil, model.NewError(model.ErrNotFound, "user not found"))
+
+		resp := patch(t, mockUserService, fiber.MIMEApplicationJSON, `{"name":"update"}`)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
+
 func TestHandler_DeleteUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	t.Run("should return 200 when delete user success", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().DeleteUser(uint(1)).Return(nil)
+		mockUserService.EXPECT().DeleteUser(adminActor, uint(1), uint(1)).Return(nil)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("DELETE", "/users/1", nil)
+		req.Header.Set("If-Match", `"1"`)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 
@@ -291,13 +529,13 @@ func TestHandler_DeleteUser(t *testing.T) {
 	t.Run("should return 400 when id is invalid", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("DELETE", "/users/invalid", nil)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 
@@ -305,17 +543,36 @@ func TestHandler_DeleteUser(t *testing.T) {
 		assert.Equal(t, 400, resp.StatusCode)
 	})
 
+	t.Run("should return 403 when policy forbids the delete", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().DeleteUser(adminActor, uint(1), uint(1)).Return(service.ErrForbidden)
+
+		app := newTestApp()
+
+		req := httptest.NewRequest("DELETE", "/users/1", nil)
+		req.Header.Set("If-Match", `"1"`)
+
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
 	t.Run("should return 500 when service return error", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().DeleteUser(uint(1)).Return(errors.New("error"))
+		mockUserService.EXPECT().DeleteUser(adminActor, uint(1), uint(1)).Return(errors.New("error"))
 
-		app := fiber.New()
+		app := newTestApp()
 
 		req := httptest.NewRequest("DELETE", "/users/1", nil)
+		req.Header.Set("If-Match", `"1"`)
 		req.Header.Set("Content-Type", "application/json")
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
 
 		resp, err := app.Test(req)
 
@@ -324,58 +581,154 @@ func TestHandler_DeleteUser(t *testing.T) {
 	})
 }
 
+func TestHandler_RestoreUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	restore := func(t *testing.T, actions UserActions, claims *service.Claims) *http.Response {
+		app := newTestApp()
+		userHandler := NewUserHandler(actions, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(claims))
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/users/1/restore", nil))
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("should return the restored user", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().RestoreUser(adminActor, uint(1)).Return(&model.User{ID: 1, Name: "restored"}, nil)
+
+		resp := restore(t, mockUserService, adminClaims)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody model.UserResponse
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, "restored", respBody.Name)
+	})
+
+	t.Run("should return 404 when the user is not deleted", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().RestoreUser(adminActor, uint(1)).Return(nil, model.NewError(model.ErrNotFound, "user not found"))
+
+		resp := restore(t, mockUserService, adminClaims)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("should return 403 for regular users", func(t *testing.T) {
+		resp := restore(t, nil, userClaims)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+}
+
 func TestHandler_GetUsers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	t.Run("should return 200 when get users success", func(t *testing.T) {
-		users := []*model.User{
-			{
-				ID:       1,
-				Name:     "test",
-				Email:    "test@gmail.com",
-				Password: "123456",
-			},
-			{
-				ID:       2,
-				Name:     "test2",
-				Email:    "test2@gmail.com",
-				Password: "123456",
-			},
+	users := []*model.User{
+		{ID: 1, Name: "test", Email: "test@gmail.com"},
+		{ID: 2, Name: "test2", Email: "test2@gmail.com"},
+	}
+
+	list := func(t *testing.T, mockUserService UserActions, target string) (int, model.UserListResponse) {
+		app := newTestApp()
+
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		assert.Nil(t, err)
+
+		var respBody model.UserListResponse
+		if resp.StatusCode == 200 {
+			assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
 		}
+		return resp.StatusCode, respBody
+	}
 
+	t.Run("should return 200 when get users success", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().GetUsers().Return(users, nil)
+		mockUserService.EXPECT().GetUsers(model.UserQuery{}).Return(&model.UserPage{Users: users, Total: 2, Page: 1, PerPage: 20}, nil)
 
-		app := fiber.New()
+		status, respBody := list(t, mockUserService, "/users")
+		assert.Equal(t, 200, status)
 
-		req := httptest.NewRequest("GET", "/users", nil)
-		req.Header.Set("Content-Type", "application/json")
+		assert.Len(t, respBody.Users, 2)
+		assert.Equal(t, int64(2), respBody.Total)
+		assert.Equal(t, model.PageLinks{}, respBody.Links)
+	})
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+	t.Run("should pass filters and sorting to the service", func(t *testing.T) {
+		after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		before := time.Date(2024, 1, 31, 23, 59, 59, int(time.Second-time.Nanosecond), time.UTC)
 
-		resp, err := app.Test(req)
-		assert.Nil(t, err)
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().GetUsers(model.UserQuery{
+			Page:          2,
+			PerPage:       10,
+			Name:          "te",
+			Email:         "gmail",
+			CreatedAfter:  &after,
+			CreatedBefore: &before,
+			Sort:          "created_at",
+			Descending:    true,
+		}).Return(&model.UserPage{Users: users, Total: 2, Page: 2, PerPage: 10}, nil)
+
+		status, _ := list(t, mockUserService, "/users?page=2&per_page=10&name=te&email=gmail&created_after=2024-01-01&created_before=2024-01-31&sort=-created_at")
+		assert.Equal(t, 200, status)
+	})
+
+	t.Run("should link to the neighbouring pages", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().GetUsers(gomock.Any()).Return(&model.UserPage{Users: users, Total: 6, Page: 2, PerPage: 2}, nil)
+
+		_, respBody := list(t, mockUserService, "/users?per_page=2&page=2&name=te")
+
+		assert.Equal(t, "/users?per_page=2&page=3&name=te", respBody.Links.Next)
+		assert.Equal(t, "/users?per_page=2&page=1&name=te", respBody.Links.Prev)
+	})
 
+	t.Run("should switch to cursor pagination", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().GetUsersAfter(model.UserQuery{PerPage: 2}, "").Return(&model.UserCursorPage{Users: users, PerPage: 2, NextCursor: "next.sig"}, nil)
+
+		app := newTestApp()
+		userHandler := NewUserHandler(mockUserService, DefaultUserRouteRoles())
+		userHandler.RegisterRoutes(app, withClaims(adminClaims))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/users?paginate=cursor&per_page=2", nil))
+		assert.Nil(t, err)
 		assert.Equal(t, 200, resp.StatusCode)
+
+		var respBody model.UserCursorListResponse
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, "next.sig", respBody.NextCursor)
+		assert.Equal(t, "/users?paginate=cursor&per_page=2&cursor=next.sig", respBody.Links.Next)
 	})
 
-	t.Run("should return 500 when service return error", func(t *testing.T) {
+	t.Run("should pass the cursor to the service", func(t *testing.T) {
 		mockUserService := mocks.NewMockUserActions(ctrl)
-		mockUserService.EXPECT().GetUsers().Return(nil, errors.New("error"))
+		mockUserService.EXPECT().GetUsersAfter(model.UserQuery{}, "next.sig").Return(&model.UserCursorPage{PerPage: 20}, nil)
 
-		app := fiber.New()
+		status, _ := list(t, mockUserService, "/users?cursor=next.sig")
+		assert.Equal(t, 200, status)
+	})
 
-		req := httptest.NewRequest("GET", "/users", nil)
-		req.Header.Set("Content-Type", "application/json")
+	t.Run("should return 400 for a cursor with offset pagination", func(t *testing.T) {
+		status, _ := list(t, mocks.NewMockUserActions(ctrl), "/users?paginate=offset&cursor=next.sig")
+		assert.Equal(t, 400, status)
+	})
 
-		userHandler := NewUserHandler(mockUserService)
-		userHandler.RegisterRoutes(app)
+	t.Run("should return 422 for invalid parameters", func(t *testing.T) {
+		status, _ := list(t, mocks.NewMockUserActions(ctrl), "/users?per_page=500&sort=password&created_after=yesterday")
+		assert.Equal(t, 422, status)
+	})
 
-		resp, err := app.Test(req)
-		assert.Nil(t, err)
+	t.Run("should return 500 when service return error", func(t *testing.T) {
+		mockUserService := mocks.NewMockUserActions(ctrl)
+		mockUserService.EXPECT().GetUsers(gomock.Any()).Return(nil, errors.New("error"))
 
-		assert.Equal(t, 500, resp.StatusCode)
+		status, _ := list(t, mockUserService, "/users")
+		assert.Equal(t, 500, status)
 	})
 }
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+
+	"golangHexagonal/internal/app/service"
+	"golangHexagonal/internal/app/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseBody decodes the request body into input and checks the rules in its
+// validate tags, so handlers only pass well-formed input to the services.
+func parseBody(c *fiber.Ctx, input any) error {
+	if err := c.BodyParser(input); err != nil {
+		return err
+	}
+	return validation.Struct(input)
+}
+
+// parseQuery is parseBody for the query string.
+func parseQuery(c *fiber.Ctx, input any) error {
+	if err := c.QueryParser(input); err != nil {
+		return err
+	}
+	return validation.Struct(input)
+}
+
+// bodyError answers a parseBody or parseQuery failure: 422 for invalid fields, 400 for a
+// body that could not be decoded.
+func bodyError(c *fiber.Ctx, err error) error {
+	if ok, err := asValidationError(c, err); ok {
+		return err
+	}
+	return problem(c, fiber.StatusBadRequest, err.Error())
+}
+
+// asValidationError reports whether err is a *service.ValidationError and,
+// if so, writes it as a 422 validation problem listing every field
+// violation.
+func asValidationError(c *fiber.Ctx, err error) (bool, error) {
+	var validationErr *service.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false, nil
+	}
+
+	p := newProblem(c, fiber.StatusUnprocessableEntity, "The request contains invalid fields")
+	p.Type = ProblemTypeValidation
+	p.Violations = validationErr.Violations
+	return true, writeProblem(c, p)
+}
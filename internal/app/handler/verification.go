@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"golangHexagonal/internal/app/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+type VerificationActions interface {
+	VerifyEmail(token string) error
+	ResendVerification(email string) error
+}
+
+type VerificationHandler struct {
+	verificationService VerificationActions
+}
+
+func NewVerificationHandler(verificationService VerificationActions) *VerificationHandler {
+	return &VerificationHandler{verificationService: verificationService}
+}
+
+// RegisterRoutes mounts the verification routes. Resending is limited per
+// client IP on top of the per-account interval enforced by the service.
+func (h *VerificationHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/verify-email", h.VerifyEmail)
+	app.Post("/verify-email/resend", limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 15 * time.Minute,
+		LimitReached: func(c *fiber.Ctx) error {
+			return problem(c, fiber.StatusTooManyRequests, "Too many requests")
+		},
+	}), h.ResendVerification)
+}
+
+func (h *VerificationHandler) VerifyEmail(c *fiber.Ctx) error {
+	var input model.VerifyEmailInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	if err := h.verificationService.VerifyEmail(input.Token); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"message": "Email address verified"})
+}
+
+// ResendVerification answers the same way whether or not the email belongs
+// to an unverified account.
+func (h *VerificationHandler) ResendVerification(c *fiber.Ctx) error {
+	var input model.ResendVerificationInput
+	if err := parseBody(c, &input); err != nil {
+		return bodyError(c, err)
+	}
+
+	if err := h.verificationService.ResendVerification(input.Email); err != nil {
+		return problem(c, fiber.StatusInternalServerError, "Could not send verification email")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "If the account needs verification, an email has been sent"})
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"golangHexagonal/internal/app/handler/mocks"
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	verify := func(t *testing.T, mockVerificationActions VerificationActions) int {
+		body, err := json.Marshal(&model.VerifyEmailInput{Token: "token"})
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("POST", "/verify-email", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		app := newTestApp()
+
+		verificationHandler := NewVerificationHandler(mockVerificationActions)
+		verificationHandler.RegisterRoutes(app)
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("should return 200 when email is verified", func(t *testing.T) {
+		mockVerificationActions := mocks.NewMockVerificationActions(ctrl)
+		mockVerificationActions.EXPECT().VerifyEmail("token").Return(nil)
+
+		assert.Equal(t, 200, verify(t, mockVerificationActions))
+	})
+
+	t.Run("should return 422 when token is invalid", func(t *testing.T) {
+		mockVerificationActions := mocks.NewMockVerificationActions(ctrl)
+		mockVerificationActions.EXPECT().VerifyEmail("token").Return(service.ErrInvalidVerificationToken)
+
+		assert.Equal(t, 422, verify(t, mockVerificationActions))
+	})
+}
+
+func TestHandler_ResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should rate limit resends per client", func(t *testing.T) {
+		mockVerificationActions := mocks.NewMockVerificationActions(ctrl)
+		mockVerificationActions.EXPECT().ResendVerification("test@gmail.com").Return(nil).Times(5)
+
+		app := newTestApp()
+
+		verificationHandler := NewVerificationHandler(mockVerificationActions)
+		verificationHandler.RegisterRoutes(app)
+
+		body, err := json.Marshal(&model.ResendVerificationInput{Email: "test@gmail.com"})
+		assert.Nil(t, err)
+
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest("POST", "/verify-email/resend", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			assert.Nil(t, err)
+			assert.Equal(t, 202, resp.StatusCode)
+		}
+
+		req := httptest.NewRequest("POST", "/verify-email/resend", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, 429, resp.StatusCode)
+	})
+}
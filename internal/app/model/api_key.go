@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes an API key can be limited to. Access tokens from an interactive
+// login are not scoped.
+const (
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+)
+
+// Scopes lists every scope that can be granted to an API key.
+var Scopes = []string{ScopeUsersRead, ScopeUsersWrite}
+
+// APIKey is a long-lived, user-managed credential for scripts. Only the hash
+// of the key is stored; Prefix keeps enough of it to recognise the key in a
+// list.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"-" gorm:"index"`
+	Name       string     `json:"name" gorm:"size:100"`
+	Prefix     string     `json:"prefix" gorm:"size:16"`
+	KeyHash    string     `json:"-" gorm:"size:64;uniqueIndex"`
+	Scopes     string     `json:"-" gorm:"size:255"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ScopeList returns the scopes granted to the key.
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,oneof=users:read users:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
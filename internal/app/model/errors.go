@@ -0,0 +1,43 @@
+package model
+
+import "errors"
+
+// Kinds of domain errors. Repositories and services return errors that match
+// one of them with errors.Is, and the HTTP layer maps each kind to a status
+// code. They live here rather than in service so that repositories can
+// return them too.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+
+	// ErrPreconditionFailed means a conditional write found another
+	// version of the record than the one the client read.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// ErrUserModified is returned by writes that expect a version of a user
+// that is no longer the stored one.
+var ErrUserModified = NewError(ErrPreconditionFailed, "user has been modified since it was read")
+
+// DomainError is an error of one of the kinds above with a message that is
+// safe to show to clients.
+type DomainError struct {
+	Kind    error
+	Message string
+}
+
+// NewError returns a DomainError of kind.
+func NewError(kind error, message string) *DomainError {
+	return &DomainError{Kind: kind, Message: message}
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Kind
+}
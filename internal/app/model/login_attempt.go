@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// LoginAttempt counts recent failed logins for one key, an email address or
+// a client IP. LockedUntil is set once the key has failed too often.
+type LoginAttempt struct {
+	Key           string `gorm:"column:throttle_key;primaryKey;size:320"`
+	Failures      int
+	LastFailureAt time.Time `gorm:"index"`
+	LockedUntil   *time.Time
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// RecoveryCode is a hashed, single-use code that replaces a TOTP code when
+// the user has lost their authenticator.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	CodeHash  string `gorm:"size:64;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAEnrollment is what an authenticator app needs to be set up: the raw
+// secret for manual entry and the otpauth URI to render as a QR code.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFACodeInput carries a TOTP code or, where accepted, a recovery code.
+type MFACodeInput struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
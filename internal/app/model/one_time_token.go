@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// OneTimeToken is a hashed, single-use, expiring token sent to a user out of
+// band, for example in a password reset email.
+type OneTimeToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	Purpose   string `gorm:"size:32;index"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
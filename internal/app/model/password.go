@@ -0,0 +1,23 @@
+package model
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerificationInput struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
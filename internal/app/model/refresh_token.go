@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// RefreshToken is a persisted, hashed refresh token. Tokens issued by the
+// same login share a FamilyID so that reuse of a rotated token can revoke
+// every descendant of that login.
+type RefreshToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	FamilyID  string `gorm:"size:64;index"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// RevokedToken records the jti of an access token that was revoked before
+// its expiry. Rows can be purged once ExpiresAt has passed.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:64"`
+	ExpiresAt time.Time `gorm:"index"`
+}
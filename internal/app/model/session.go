@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Session is one login on one device. Access tokens carry its ID in the sid
+// claim and refresh tokens of the login use it as their FamilyID, so revoking
+// the session ends both.
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey;size:32"`
+	UserID     uint       `json:"-" gorm:"index"`
+	UserAgent  string     `json:"user_agent" gorm:"size:255"`
+	IP         string     `json:"ip" gorm:"size:45"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"-"`
+
+	// Current marks the session of the caller in a listing.
+	Current bool `json:"current" gorm:"-"`
+}
@@ -1,8 +1,111 @@
 package model
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// User is the account entity. It is not meant to be serialized to clients;
+// handlers answer with UserResponse. Password holds the hash and is never
+// encoded, even if a User ends up in a response by mistake.
+//
+// Deleting a user only sets DeletedAt, which hides the account from every
+// query until it is restored or purged. The email address stays taken in
+// the meantime, so a restore can never clash with a newer account.
 type User struct {
-	ID       uint   `json:"id" gorm:"primaryKey"`
+	ID       uint   `json:"id" gorm:"primaryKey;index:idx_users_created_at_id,priority:2"`
 	Name     string `json:"name"`
 	Email    string `json:"email" gorm:"unique"`
-	Password string `json:"password"`
+	Password string `json:"-"`
+	Role     string `json:"role" gorm:"size:16;not null;default:user"`
+
+	EmailVerified bool `json:"email_verified" gorm:"not null;default:false"`
+
+	// MFASecret is the base32 TOTP secret. It is stored when enrollment
+	// starts but only asked for at login once MFAEnabled is set.
+	MFASecret  string `json:"-" gorm:"size:64"`
+	MFAEnabled bool   `json:"mfa_enabled" gorm:"not null;default:false"`
+	// MFALastStep is the TOTP time step of the last accepted code. Codes of
+	// that step or earlier are refused, so none can be used twice.
+	MFALastStep uint64 `json:"-" gorm:"not null;default:0"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP(3);index:idx_users_created_at_id,priority:1"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP(3)"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Version is incremented by every write to the account other than
+	// recording used TOTP steps. Profile updates and deletes name the version
+	// they expect, so that concurrent writers cannot overwrite each other.
+	Version uint `json:"-" gorm:"not null;default:1"`
+}
+
+// CreateUserInput is the sign-up request body.
+type CreateUserInput struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ToUser maps the input to a new account.
+func (in CreateUserInput) ToUser() *User {
+	return &User{Name: in.Name, Email: in.Email, Password: in.Password}
+}
+
+// UpdateUserInput is the PUT request body. It replaces the whole profile;
+// the ID comes from the path and passwords are changed through
+// ChangePasswordInput instead.
+type UpdateUserInput struct {
+	Name  string `json:"name" validate:"required,max=100"`
+	Email string `json:"email" validate:"required,email,max=254"`
+}
+
+// ToUser maps the input to version of the account with id.
+func (in UpdateUserInput) ToUser(id, version uint) *User {
+	return &User{ID: id, Name: in.Name, Email: in.Email, Version: version}
+}
+
+// UserPatch lists the profile fields a partial update changes. A nil field
+// is left as it is.
+type UserPatch struct {
+	Name  *string `json:"name" validate:"required,max=100"`
+	Email *string `json:"email" validate:"required,email,max=254"`
+}
+
+// Apply copies the fields set in the patch to user.
+func (p UserPatch) Apply(user *User) {
+	if p.Name != nil {
+		user.Name = *p.Name
+	}
+	if p.Email != nil {
+		user.Email = *p.Email
+	}
+}
+
+// UserResponse is the public view of a user.
+type UserResponse struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewUserResponse(user *User) UserResponse {
+	return UserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+		MFAEnabled:    user.MFAEnabled,
+		CreatedAt:     user.CreatedAt,
+	}
 }
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DefaultUsersPerPage = 20
+	MaxUsersPerPage     = 100
+)
+
+// UserSortFields are the fields users can be sorted by.
+var UserSortFields = []string{"id", "name", "email", "created_at"}
+
+// UserQuery selects one page of users. Name and Email match substrings;
+// the creation date bounds are inclusive. Sort is one of UserSortFields;
+// ties are broken by ID.
+type UserQuery struct {
+	Page          int
+	PerPage       int
+	Name          string
+	Email         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          string
+	Descending    bool
+}
+
+// Offset is the number of users before the page.
+func (q UserQuery) Offset() int {
+	return (q.Page - 1) * q.PerPage
+}
+
+// UserPage is one page of users along with the number of users that match
+// the query on all pages.
+type UserPage struct {
+	Users   []*User
+	Total   int64
+	Page    int
+	PerPage int
+}
+
+// HasNext reports whether there are users after this page.
+func (p *UserPage) HasNext() bool {
+	return int64(p.Page*p.PerPage) < p.Total
+}
+
+// UserCursor is a keyset position: the creation time and ID of the last user
+// of a page.
+type UserCursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// UserCursorPage is one page of a keyset listing. NextCursor is empty on the
+// last page.
+type UserCursorPage struct {
+	Users      []*User
+	PerPage    int
+	NextCursor string
+}
+
+// ListUsersInput holds the query parameters of GET /users. Sort takes a
+// field name, prefixed with "-" for descending order.
+//
+// Passing paginate=cursor, or a cursor, switches to keyset pagination on
+// (created_at, id): pages are then always sorted by creation, have no total
+// and ignore page and sort. A cursor only works with the filters it was
+// issued for and cannot be combined with paginate=offset.
+type ListUsersInput struct {
+	Page          int    `query:"page" json:"page" validate:"min=1"`
+	PerPage       int    `query:"per_page" json:"per_page" validate:"min=1,max=100"`
+	Name          string `query:"name" json:"name" validate:"max=100"`
+	Email         string `query:"email" json:"email" validate:"max=254"`
+	CreatedAfter  string `query:"created_after" json:"created_after" validate:"datetime"`
+	CreatedBefore string `query:"created_before" json:"created_before" validate:"datetime"`
+	Sort          string `query:"sort" json:"sort" validate:"oneof=id -id name -name email -email created_at -created_at"`
+	Paginate      string `query:"paginate" json:"paginate" validate:"oneof=offset cursor"`
+	Cursor        string `query:"cursor" json:"cursor" validate:"max=256"`
+}
+
+// UsesCursor reports whether the input asks for keyset pagination.
+func (in ListUsersInput) UsesCursor() bool {
+	return in.Paginate == "cursor" || in.Cursor != ""
+}
+
+// ToQuery maps validated input to a query. Dates without a time cover the
+// whole day: created_before=2024-01-31 includes users created that day.
+func (in ListUsersInput) ToQuery() UserQuery {
+	query := UserQuery{
+		Page:    in.Page,
+		PerPage: in.PerPage,
+		Name:    in.Name,
+		Email:   in.Email,
+		Sort:    strings.TrimPrefix(in.Sort, "-"),
+	}
+	query.Descending = query.Sort != in.Sort
+
+	if after, err := ParseTimestamp(in.CreatedAfter); err == nil {
+		query.CreatedAfter = &after
+	}
+	if before, err := ParseTimestamp(in.CreatedBefore); err == nil {
+		if isDate(in.CreatedBefore) {
+			before = before.Add(24*time.Hour - time.Nanosecond)
+		}
+		query.CreatedBefore = &before
+	}
+	return query
+}
+
+// ParseTimestamp accepts an RFC 3339 timestamp or a YYYY-MM-DD date, which
+// is read as midnight UTC.
+func ParseTimestamp(value string) (time.Time, error) {
+	if isDate(value) {
+		return time.Parse(time.DateOnly, value)
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func isDate(value string) bool {
+	return len(value) == len(time.DateOnly)
+}
+
+// UserListResponse is the body of GET /users. Failures are reported as
+// problem details like everywhere else, so it has no status fields. Links
+// are relative URLs of the neighbouring pages and are omitted at the ends.
+type UserListResponse struct {
+	Users   []UserResponse `json:"users"`
+	Total   int64          `json:"total"`
+	Page    int            `json:"page"`
+	PerPage int            `json:"per_page"`
+	Links   PageLinks      `json:"links"`
+}
+
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// UserCursorListResponse is the body of GET /users in cursor mode.
+type UserCursorListResponse struct {
+	Users      []UserResponse `json:"users"`
+	PerPage    int            `json:"per_page"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Links      PageLinks      `json:"links"`
+}
+
+// NewUserCursorListResponse maps page to a response. The caller fills in
+// Links.
+func NewUserCursorListResponse(page *UserCursorPage) UserCursorListResponse {
+	responses := make([]UserResponse, len(page.Users))
+	for i, user := range page.Users {
+		responses[i] = NewUserResponse(user)
+	}
+	return UserCursorListResponse{Users: responses, PerPage: page.PerPage, NextCursor: page.NextCursor}
+}
+
+// NewUserListResponse maps page to a response. The caller fills in Links.
+func NewUserListResponse(page *UserPage) UserListResponse {
+	responses := make([]UserResponse, len(page.Users))
+	for i, user := range page.Users {
+		responses[i] = NewUserResponse(user)
+	}
+	return UserListResponse{Users: responses, Total: page.Total, Page: page.Page, PerPage: page.PerPage}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+)
+
+type IAPIKeyRepository interface {
+	CreateAPIKey(key *model.APIKey) error
+	FindAPIKeyByHash(hash string) (*model.APIKey, error)
+	FindUserAPIKeys(userID uint) ([]*model.APIKey, error)
+	RevokeAPIKey(userID, id uint) (bool, error)
+	TouchAPIKey(id uint, usedAt time.Time) error
+}
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(key *model.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *APIKeyRepository) FindAPIKeyByHash(hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	result := r.db.Where("key_hash = ?", hash).First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) FindUserAPIKeys(userID uint) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+// RevokeAPIKey revokes a key of the user. It reports false when the user has
+// no such key or it was already revoked.
+func (r *APIKeyRepository) RevokeAPIKey(userID, id uint) (bool, error) {
+	result := r.db.Model(&model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *APIKeyRepository) TouchAPIKey(id uint, usedAt time.Time) error {
+	return r.db.Model(&model.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+
+	"golangHexagonal/internal/app/model"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// MySQL error numbers translated by translateError.
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlRowIsReferenced = 1451
+	mysqlNoReferencedRow = 1452
+)
+
+// translateError turns GORM and MySQL errors into domain errors about
+// entity, such as "user not found". Other errors are returned unchanged;
+// the HTTP layer reports them as internal errors without their message.
+func translateError(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.NewError(model.ErrNotFound, entity+" not found")
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return model.NewError(model.ErrConflict, entity+" already exists")
+	}
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return model.NewError(model.ErrConflict, entity+" is referenced by other records")
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlDuplicateEntry:
+			return model.NewError(model.ErrConflict, entity+" already exists")
+		case mysqlRowIsReferenced, mysqlNoReferencedRow:
+			return model.NewError(model.ErrConflict, entity+" is referenced by other records")
+		}
+	}
+
+	return err
+}
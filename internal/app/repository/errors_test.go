@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"golangHexagonal/internal/app/model"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRepository_TranslateError(t *testing.T) {
+	t.Run("should translate a missing record to not found", func(t *testing.T) {
+		err := translateError(fmt.Errorf("query: %w", gorm.ErrRecordNotFound), "user")
+
+		assert.True(t, errors.Is(err, model.ErrNotFound))
+		assert.Equal(t, "user not found", err.Error())
+	})
+
+	t.Run("should translate a duplicate key to conflict without the database message", func(t *testing.T) {
+		err := translateError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.email'"}, "user")
+
+		assert.True(t, errors.Is(err, model.ErrConflict))
+		assert.Equal(t, "user already exists", err.Error())
+	})
+
+	t.Run("should translate GORM's duplicate key error to conflict", func(t *testing.T) {
+		assert.True(t, errors.Is(translateError(gorm.ErrDuplicatedKey, "user"), model.ErrConflict))
+	})
+
+	t.Run("should leave other errors unchanged", func(t *testing.T) {
+		err := errors.New("connection refused")
+
+		assert.Equal(t, err, translateError(err, "user"))
+		assert.Nil(t, translateError(nil, "user"))
+	})
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ILoginAttemptRepository interface {
+	FindLoginAttempt(key string) (*model.LoginAttempt, error)
+	RecordLoginFailure(key string, now time.Time, window time.Duration) (*model.LoginAttempt, error)
+	LockLoginAttempt(key string, until time.Time) error
+	ResetLoginAttempts(key string) error
+	PurgeLoginAttempts(before, now time.Time) (int64, error)
+}
+
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// FindLoginAttempt returns nil without an error when key has no recorded
+// failures.
+func (r *LoginAttemptRepository) FindLoginAttempt(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	result := r.db.Where("throttle_key = ?", key).First(&attempt)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &attempt, nil
+}
+
+// RecordLoginFailure adds a failure to key and returns the updated counter.
+// Failures older than window are forgotten first. The row is locked for the
+// update so that concurrent failures are all counted.
+func (r *LoginAttemptRepository) RecordLoginFailure(key string, now time.Time, window time.Duration) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("throttle_key = ?", key).First(&attempt)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			attempt = model.LoginAttempt{Key: key, Failures: 1, LastFailureAt: now}
+			return tx.Create(&attempt).Error
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if now.Sub(attempt.LastFailureAt) > window {
+			attempt.Failures = 0
+		}
+		attempt.Failures++
+		attempt.LastFailureAt = now
+		return tx.Save(&attempt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) LockLoginAttempt(key string, until time.Time) error {
+	return r.db.Model(&model.LoginAttempt{}).Where("throttle_key = ?", key).Update("locked_until", until).Error
+}
+
+func (r *LoginAttemptRepository) ResetLoginAttempts(key string) error {
+	return r.db.Where("throttle_key = ?", key).Delete(&model.LoginAttempt{}).Error
+}
+
+// PurgeLoginAttempts removes counters whose last failure is older than before
+// and that are not locked at now.
+func (r *LoginAttemptRepository) PurgeLoginAttempts(before, now time.Time) (int64, error) {
+	result := r.db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, now).
+		Delete(&model.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+)
+
+type IOneTimeTokenRepository interface {
+	CreateOneTimeToken(token *model.OneTimeToken) error
+	FindOneTimeToken(purpose, hash string) (*model.OneTimeToken, error)
+	FindLatestOneTimeToken(userID uint, purpose string) (*model.OneTimeToken, error)
+	ConsumeOneTimeToken(id uint) (bool, error)
+	DeleteUserOneTimeTokens(userID uint, purpose string) error
+}
+
+type OneTimeTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewOneTimeTokenRepository(db *gorm.DB) *OneTimeTokenRepository {
+	return &OneTimeTokenRepository{db: db}
+}
+
+func (r *OneTimeTokenRepository) CreateOneTimeToken(token *model.OneTimeToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *OneTimeTokenRepository) FindOneTimeToken(purpose, hash string) (*model.OneTimeToken, error) {
+	var token model.OneTimeToken
+	result := r.db.Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+func (r *OneTimeTokenRepository) FindLatestOneTimeToken(userID uint, purpose string) (*model.OneTimeToken, error) {
+	var token model.OneTimeToken
+	result := r.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at DESC").First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// ConsumeOneTimeToken marks the token as used. It reports false when the
+// token had already been used, so two concurrent requests cannot both
+// redeem it.
+func (r *OneTimeTokenRepository) ConsumeOneTimeToken(id uint) (bool, error) {
+	result := r.db.Model(&model.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *OneTimeTokenRepository) DeleteUserOneTimeTokens(userID uint, purpose string) error {
+	return r.db.Where("user_id = ? AND purpose = ?", userID, purpose).Delete(&model.OneTimeToken{}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+)
+
+type IRecoveryCodeRepository interface {
+	ReplaceRecoveryCodes(userID uint, codes []*model.RecoveryCode) error
+	ConsumeRecoveryCode(userID uint, hash string) (bool, error)
+	DeleteRecoveryCodes(userID uint) error
+}
+
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+// ReplaceRecoveryCodes drops every code of the user and stores codes in their
+// place, in one transaction.
+func (r *RecoveryCodeRepository) ReplaceRecoveryCodes(userID uint, codes []*model.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(codes).Error
+	})
+}
+
+// ConsumeRecoveryCode marks the matching unused code as used. It reports
+// false when no such code exists, so a code can only be redeemed once.
+func (r *RecoveryCodeRepository) ConsumeRecoveryCode(userID uint, hash string) (bool, error) {
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *RecoveryCodeRepository) DeleteRecoveryCodes(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+}
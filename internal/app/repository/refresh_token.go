@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+)
+
+type IRefreshTokenRepository interface {
+	CreateRefreshToken(token *model.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*model.RefreshToken, error)
+	RotateRefreshToken(currentID uint, next *model.RefreshToken) (bool, error)
+	RevokeRefreshTokenFamily(familyID string) error
+	RevokeUserRefreshTokens(userID uint) error
+}
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *RefreshTokenRepository) FindRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	result := r.db.Where("token_hash = ?", hash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken marks the current token as rotated and stores its
+// successor in one transaction. It reports false when the current token was
+// already rotated or revoked by a concurrent request.
+func (r *RefreshTokenRepository) RotateRefreshToken(currentID uint, next *model.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", currentID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		rotated = true
+		return tx.Create(next).Error
+	})
+	return rotated, err
+}
+
+func (r *RefreshTokenRepository) RevokeRefreshTokenFamily(familyID string) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *RefreshTokenRepository) RevokeUserRefreshTokens(userID uint) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRevocationRepository interface {
+	RevokeToken(jti string, expiresAt time.Time) error
+	IsTokenRevoked(jti string) (bool, error)
+	PurgeExpiredRevocations(now time.Time) (int64, error)
+}
+
+type RevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewRevocationRepository(db *gorm.DB) *RevocationRepository {
+	return &RevocationRepository{db: db}
+}
+
+func (r *RevocationRepository) RevokeToken(jti string, expiresAt time.Time) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+func (r *RevocationRepository) IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	result := r.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count)
+	return count > 0, result.Error
+}
+
+func (r *RevocationRepository) PurgeExpiredRevocations(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&model.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryRevocationRepository keeps revoked token IDs in process memory. It
+// suits single-instance deployments and tests; revocations are lost on
+// restart and are not shared between instances.
+type MemoryRevocationRepository struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryRevocationRepository() *MemoryRevocationRepository {
+	return &MemoryRevocationRepository{revoked: make(map[string]time.Time)}
+}
+
+func (r *MemoryRevocationRepository) RevokeToken(jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *MemoryRevocationRepository) IsTokenRevoked(jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
+
+func (r *MemoryRevocationRepository) PurgeExpiredRevocations(now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for jti, expiresAt := range r.revoked {
+		if expiresAt.Before(now) {
+			delete(r.revoked, jti)
+			purged++
+		}
+	}
+	return purged, nil
+}
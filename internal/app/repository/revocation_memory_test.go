@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRevocationRepository(t *testing.T) {
+	t.Run("should report revoked tokens", func(t *testing.T) {
+		repo := NewMemoryRevocationRepository()
+		err := repo.RevokeToken("jti", time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+
+		revoked, err := repo.IsTokenRevoked("jti")
+		assert.Nil(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.IsTokenRevoked("other")
+		assert.Nil(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("should purge only expired entries", func(t *testing.T) {
+		now := time.Now()
+		repo := NewMemoryRevocationRepository()
+		assert.Nil(t, repo.RevokeToken("expired", now.Add(-time.Minute)))
+		assert.Nil(t, repo.RevokeToken("active", now.Add(time.Minute)))
+
+		purged, err := repo.PurgeExpiredRevocations(now)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		revoked, _ := repo.IsTokenRevoked("expired")
+		assert.False(t, revoked)
+		revoked, _ = repo.IsTokenRevoked("active")
+		assert.True(t, revoked)
+	})
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"gorm.io/gorm"
+)
+
+type ISessionRepository interface {
+	CreateSession(session *model.Session) error
+	FindSession(id string) (*model.Session, error)
+	FindUserSessions(userID uint) ([]*model.Session, error)
+	TouchSession(id string, seenAt time.Time) error
+	RevokeSession(userID uint, id string) (bool, error)
+	RevokeOtherSessions(userID uint, keepID string) ([]string, error)
+}
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) CreateSession(session *model.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *SessionRepository) FindSession(id string) (*model.Session, error) {
+	var session model.Session
+	result := r.db.Where("id = ?", id).First(&session)
+	if result.Error != nil {
+		return nil, translateError(result.Error, "session")
+	}
+	return &session, nil
+}
+
+// FindUserSessions returns the sessions of the user that were not revoked,
+// most recently used first.
+func (r *SessionRepository) FindUserSessions(userID uint) ([]*model.Session, error) {
+	var sessions []*model.Session
+	result := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).Order("last_seen_at DESC").Find(&sessions)
+	return sessions, result.Error
+}
+
+func (r *SessionRepository) TouchSession(id string, seenAt time.Time) error {
+	return r.db.Model(&model.Session{}).Where("id = ?", id).Update("last_seen_at", seenAt).Error
+}
+
+// RevokeSession revokes a session of the user. It reports false when the
+// user has no such active session.
+func (r *SessionRepository) RevokeSession(userID uint, id string) (bool, error) {
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// RevokeOtherSessions revokes every active session of the user except keepID
+// and returns the IDs it revoked.
+func (r *SessionRepository) RevokeOtherSessions(userID uint, keepID string) ([]string, error) {
+	var ids []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		return tx.Model(&model.Session{}).Where("id IN ?", ids).Update("revoked_at", time.Now()).Error
+	})
+	return ids, err
+}
@@ -1,18 +1,36 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"golangHexagonal/internal/app/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// IRepository stores users. Deleted users are kept until they are purged
+// but no other method finds them. A missing user is reported as an error
+// matching model.ErrNotFound, a taken email address as one matching
+// model.ErrConflict and a write expecting an outdated version as one
+// matching model.ErrPreconditionFailed.
 type IRepository interface {
 	CreateUser(user *model.User) error
 	FindUserByID(id uint) (*model.User, error)
 	UpdateUser(user *model.User) error
-	DeleteUser(id uint) error
+	DeleteUser(id, version uint) error
+	RestoreUser(id uint) error
+	PurgeDeletedUsers(before time.Time) (int64, error)
 	FindUserByEmail(email string) (*model.User, error)
-	FindUsers() ([]*model.User, error)
+	QueryUsers(query model.UserQuery) (*model.UserPage, error)
+	SeekUsers(query model.UserQuery, after *model.UserCursor) ([]*model.User, error)
+	CountUsersByRole(role string) (int64, error)
+	CountDeletedUsersByRole(role string) (int64, error)
+	UpdatePassword(id uint, hash string) error
+	MarkEmailVerified(id uint) error
+	UpdateMFA(id uint, secret string, enabled bool) error
+	RecordMFAStep(id uint, step uint64) (bool, error)
 }
 
 type UserRepository struct {
@@ -25,34 +43,268 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 func (r *UserRepository) CreateUser(user *model.User) error {
-	return r.db.Create(user).Error
+	user.Version = 1
+	return translateError(r.db.Create(user).Error, "user")
 }
 
 func (r *UserRepository) FindUserByID(id uint) (*model.User, error) {
 	var user model.User
-	result := r.db.First(&user, id)
-	return &user, result.Error
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, translateError(err, "user")
+	}
+	return &user, nil
 }
 
+// userProfileColumns are the columns UpdateUser writes. Credentials, the
+// role and MFA settings have their own update methods.
+var userProfileColumns = []string{"name", "email", "email_verified", "updated_at", "version"}
+
+// nextVersion is the assignment every write to a user makes.
+var nextVersion = gorm.Expr("version + 1")
+
+// UpdateUser writes the profile columns of user, including zero values, if
+// the stored user still has user.Version. The version is then incremented.
 func (r *UserRepository) UpdateUser(user *model.User) error {
-	return r.db.Save(user).Error
+	expected := user.Version
+	user.Version++
+	result := r.db.Model(user).Where("version = ?", expected).Select(userProfileColumns).Updates(user)
+	if result.Error != nil {
+		user.Version = expected
+		return translateError(result.Error, "user")
+	}
+	if result.RowsAffected == 0 {
+		user.Version = expected
+		return r.missingOrModified(user.ID)
+	}
+	return nil
 }
 
-func (r *UserRepository) DeleteUser(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
+// DeleteUser soft-deletes the user if it still has version. The version is
+// incremented, so that ETags read before the delete do not match the user
+// once it is restored.
+func (r *UserRepository) DeleteUser(id, version uint) error {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(map[string]interface{}{"deleted_at": r.db.NowFunc(), "version": nextVersion})
+	if result.Error != nil {
+		return translateError(result.Error, "user")
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrModified(id)
+	}
+	return nil
+}
+
+// missingOrModified explains why a conditional write to the user matched no
+// row.
+func (r *UserRepository) missingOrModified(id uint) error {
+	var count int64
+	if err := r.db.Model(&model.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return translateError(err, "user")
+	}
+	if count == 0 {
+		return translateError(gorm.ErrRecordNotFound, "user")
+	}
+	return model.ErrUserModified
+}
+
+// RestoreUser undoes DeleteUser. It reports not found unless the user is
+// deleted and not purged yet.
+func (r *UserRepository) RestoreUser(id uint) error {
+	result := r.db.Unscoped().Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": nextVersion})
+	if result.Error != nil {
+		return translateError(result.Error, "user")
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "user")
+	}
+	return nil
+}
+
+// userOwnedModels are the records PurgeDeletedUsers removes along with their
+// user.
+var userOwnedModels = []any{
+	&model.Session{},
+	&model.RefreshToken{},
+	&model.APIKey{},
+	&model.RecoveryCode{},
+	&model.OneTimeToken{},
+}
+
+// PurgeDeletedUsers permanently removes the users deleted before the given
+// time, together with their sessions, tokens, API keys and recovery codes.
+func (r *UserRepository) PurgeDeletedUsers(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Unscoped().Model(&model.User{}).Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, owned := range userOwnedModels {
+			if err := tx.Where("user_id IN ?", ids).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Delete(&model.User{}, ids)
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, translateError(err, "user")
 }
 
 func (r *UserRepository) FindUserByEmail(email string) (*model.User, error) {
 	var user model.User
 	result := r.db.Where("email = ?", email).First(&user)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, translateError(result.Error, "user")
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) FindUsers() ([]*model.User, error) {
+// userSortColumns whitelists the columns QueryUsers sorts by.
+var userSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"email":      "email",
+	"created_at": "created_at",
+}
+
+// QueryUsers returns one page of the users matching query, sorted by
+// query.Sort and then by ID so that pages never overlap.
+func (r *UserRepository) QueryUsers(query model.UserQuery) (*model.UserPage, error) {
+	filtered := r.filterUsers(query)
+
+	page := &model.UserPage{Page: query.Page, PerPage: query.PerPage}
+	if err := filtered.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, translateError(err, "user")
+	}
+
+	column, ok := userSortColumns[query.Sort]
+	if !ok {
+		column = "id"
+	}
+
+	err := filtered.Session(&gorm.Session{}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: query.Descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: query.Descending}).
+		Limit(query.PerPage).
+		Offset(query.Offset()).
+		Find(&page.Users).Error
+	if err != nil {
+		return nil, translateError(err, "user")
+	}
+	return page, nil
+}
+
+// SeekUsers returns up to query.PerPage users matching the filters of query
+// that come after the position after in (created_at, id) order; after may
+// be nil for the first page. Unlike offsets, the position stays valid while
+// users are added or removed. created_at is NOT NULL, so every user has a
+// position.
+func (r *UserRepository) SeekUsers(query model.UserQuery, after *model.UserCursor) ([]*model.User, error) {
+	seek := r.filterUsers(query)
+	if after != nil {
+		seek = seek.Where("created_at > ? OR (created_at = ? AND id > ?)", after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
 	var users []*model.User
-	result := r.db.Find(&users)
-	return users, result.Error
+	err := seek.Order("created_at").Order("id").Limit(query.PerPage).Find(&users).Error
+	if err != nil {
+		return nil, translateError(err, "user")
+	}
+	return users, nil
+}
+
+// filterUsers applies the filters of query.
+func (r *UserRepository) filterUsers(query model.UserQuery) *gorm.DB {
+	filtered := r.db.Model(&model.User{})
+	if query.Name != "" {
+		filtered = filtered.Where("name LIKE ?", containsPattern(query.Name))
+	}
+	if query.Email != "" {
+		filtered = filtered.Where("email LIKE ?", containsPattern(query.Email))
+	}
+	if query.CreatedAfter != nil {
+		filtered = filtered.Where("created_at >= ?", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		filtered = filtered.Where("created_at <= ?", *query.CreatedBefore)
+	}
+	return filtered
+}
+
+// containsPattern returns a LIKE pattern matching values that contain s.
+func containsPattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+	return "%" + s + "%"
+}
+
+func (r *UserRepository) CountUsersByRole(role string) (int64, error) {
+	var count int64
+	result := r.db.Model(&model.User{}).Where("role = ?", role).Count(&count)
+	return count, result.Error
+}
+
+// CountDeletedUsersByRole counts the deleted users with role that are not
+// purged yet.
+func (r *UserRepository) CountDeletedUsersByRole(role string) (int64, error) {
+	var count int64
+	result := r.db.Unscoped().Model(&model.User{}).Where("role = ? AND deleted_at IS NOT NULL", role).Count(&count)
+	return count, result.Error
+}
+
+func (r *UserRepository) UpdatePassword(id uint, hash string) error {
+	return r.updateUser(id, map[string]interface{}{
+		"password": hash,
+		"version":  nextVersion,
+	})
+}
+
+func (r *UserRepository) MarkEmailVerified(id uint) error {
+	return r.updateUser(id, map[string]interface{}{
+		"email_verified": true,
+		"version":        nextVersion,
+	})
+}
+
+func (r *UserRepository) UpdateMFA(id uint, secret string, enabled bool) error {
+	return r.updateUser(id, map[string]interface{}{
+		"mfa_secret":  secret,
+		"mfa_enabled": enabled,
+		"version":     nextVersion,
+	})
+}
+
+// updateUser applies updates, which must include the next version, to the
+// user with id. It reports not found when there is no such user, for example
+// because they were deleted meanwhile. The version always changes, so a
+// matched row is never reported as unaffected.
+func (r *UserRepository) updateUser(id uint, updates map[string]interface{}) error {
+	result := r.db.Model(&model.User{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return translateError(result.Error, "user")
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound, "user")
+	}
+	return nil
+}
+
+// RecordMFAStep marks the TOTP time step as used. It reports false when the
+// user already used that step or a later one.
+func (r *UserRepository) RecordMFAStep(id uint, step uint64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND mfa_last_step < ?", id, step).
+		Update("mfa_last_step", step)
+	if result.Error != nil {
+		return false, translateError(result.Error, "user")
+	}
+	return result.RowsAffected == 1, nil
 }
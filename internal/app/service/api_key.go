@@ -0,0 +1,140 @@
+package service
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// apiKeyPrefix marks API keys so that they are easy to spot in logs and
+// secret scanners.
+const apiKeyPrefix = "hxk_"
+
+// apiKeyDisplayLength is how much of a key is kept in clear text.
+const apiKeyDisplayLength = 12
+
+var (
+	ErrInvalidAPIKey    = model.NewError(model.ErrUnauthorized, "invalid, expired or revoked api key")
+	ErrAPIKeyNotFound   = model.NewError(model.ErrNotFound, "api key not found")
+	ErrInvalidScope     = model.NewError(model.ErrValidation, "unknown or missing api key scope")
+	ErrInvalidAPIKeyTTL = model.NewError(model.ErrValidation, "api key expiry must be in the future")
+)
+
+type APIKeyService struct {
+	keys  repository.IAPIKeyRepository
+	users repository.IRepository
+	now   func() time.Time
+}
+
+func NewAPIKeyService(keys repository.IAPIKeyRepository, users repository.IRepository) *APIKeyService {
+	return &APIKeyService{keys: keys, users: users, now: time.Now}
+}
+
+// CreateAPIKey issues a key for the user and returns it in clear text along
+// with its record. The clear text is not stored and cannot be shown again.
+func (s *APIKeyService) CreateAPIKey(userID uint, input *model.CreateAPIKeyInput) (string, *model.APIKey, error) {
+	if err := validateScopes(input.Scopes); err != nil {
+		return "", nil, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(s.now()) {
+		return "", nil, ErrInvalidAPIKeyTTL
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+	key := apiKeyPrefix + secret
+
+	record := &model.APIKey{
+		UserID:    userID,
+		Name:      input.Name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashToken(key),
+		Scopes:    strings.Join(input.Scopes, " "),
+		ExpiresAt: input.ExpiresAt,
+	}
+	if err := s.keys.CreateAPIKey(record); err != nil {
+		return "", nil, err
+	}
+
+	return key, record, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(userID uint) ([]*model.APIKey, error) {
+	return s.keys.FindUserAPIKeys(userID)
+}
+
+func (s *APIKeyService) RevokeAPIKey(userID, id uint) error {
+	revoked, err := s.keys.RevokeAPIKey(userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey implements handler.APIKeyAuthenticator. The claims carry
+// the owner's current role and the scopes of the key.
+func (s *APIKeyService) AuthenticateAPIKey(key string) (*Claims, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	record, err := s.keys.FindAPIKeyByHash(hashToken(key))
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := s.now()
+	if record.RevokedAt != nil || (record.ExpiresAt != nil && !now.Before(*record.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.users.FindUserByID(record.UserID)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err := s.keys.TouchAPIKey(record.ID, now); err != nil {
+		log.Printf("record use of api key %d: %v", record.ID, err)
+	}
+
+	return &Claims{
+		UserID:   user.ID,
+		Role:     user.Role,
+		APIKeyID: record.ID,
+		Scopes:   record.ScopeList(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: strconv.FormatUint(uint64(user.ID), 10),
+		},
+	}, nil
+}
+
+func validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return ErrInvalidScope
+	}
+
+	for _, scope := range scopes {
+		known := false
+		for _, candidate := range model.Scopes {
+			if scope == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return ErrInvalidScope
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should store only the hash of the key", func(t *testing.T) {
+		mockKeyRepo := mocks.NewMockIAPIKeyRepository(ctrl)
+
+		var stored *model.APIKey
+		mockKeyRepo.EXPECT().CreateAPIKey(gomock.Any()).DoAndReturn(func(key *model.APIKey) error {
+			stored = key
+			return nil
+		})
+
+		srv := NewAPIKeyService(mockKeyRepo, nil)
+		key, record, err := srv.CreateAPIKey(1, &model.CreateAPIKeyInput{Name: "ci", Scopes: []string{model.ScopeUsersRead, model.ScopeUsersWrite}})
+		assert.Nil(t, err)
+
+		assert.True(t, strings.HasPrefix(key, apiKeyPrefix))
+		assert.Equal(t, stored, record)
+		assert.Equal(t, hashToken(key), stored.KeyHash)
+		assert.NotContains(t, stored.Prefix+stored.Name+stored.Scopes, key)
+		assert.Equal(t, []string{model.ScopeUsersRead, model.ScopeUsersWrite}, stored.ScopeList())
+	})
+
+	t.Run("should reject unknown or missing scopes", func(t *testing.T) {
+		srv := NewAPIKeyService(nil, nil)
+
+		_, _, err := srv.CreateAPIKey(1, &model.CreateAPIKeyInput{Name: "ci"})
+		assert.Equal(t, ErrInvalidScope, err)
+
+		_, _, err = srv.CreateAPIKey(1, &model.CreateAPIKeyInput{Name: "ci", Scopes: []string{"users:admin"}})
+		assert.Equal(t, ErrInvalidScope, err)
+	})
+
+	t.Run("should reject an expiry in the past", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		srv := NewAPIKeyService(nil, nil)
+
+		_, _, err := srv.CreateAPIKey(1, &model.CreateAPIKeyInput{Name: "ci", Scopes: []string{model.ScopeUsersRead}, ExpiresAt: &past})
+		assert.Equal(t, ErrInvalidAPIKeyTTL, err)
+	})
+}
+
+func TestService_AuthenticateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := apiKeyPrefix + "secret"
+
+	t.Run("should return scoped claims with the owner's role", func(t *testing.T) {
+		mockKeyRepo := mocks.NewMockIAPIKeyRepository(ctrl)
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockKeyRepo.EXPECT().FindAPIKeyByHash(hashToken(key)).Return(&model.APIKey{ID: 7, UserID: 1, Scopes: model.ScopeUsersRead}, nil)
+		mockUserRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Role: model.RoleAdmin}, nil)
+		mockKeyRepo.EXPECT().TouchAPIKey(uint(7), gomock.Any()).Return(nil)
+
+		srv := NewAPIKeyService(mockKeyRepo, mockUserRepo)
+		claims, err := srv.AuthenticateAPIKey(key)
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint(1), claims.UserID)
+		assert.Equal(t, model.RoleAdmin, claims.Role)
+		assert.True(t, claims.HasScope(model.ScopeUsersRead))
+		assert.False(t, claims.HasScope(model.ScopeUsersWrite))
+	})
+
+	t.Run("should reject revoked and expired keys", func(t *testing.T) {
+		past := time.Now().Add(-time.Minute)
+		for _, record := range []*model.APIKey{
+			{ID: 7, UserID: 1, RevokedAt: &past},
+			{ID: 7, UserID: 1, ExpiresAt: &past},
+		} {
+			mockKeyRepo := mocks.NewMockIAPIKeyRepository(ctrl)
+			mockKeyRepo.EXPECT().FindAPIKeyByHash(hashToken(key)).Return(record, nil)
+
+			srv := NewAPIKeyService(mockKeyRepo, nil)
+			claims, err := srv.AuthenticateAPIKey(key)
+			assert.Equal(t, ErrInvalidAPIKey, err)
+			assert.Nil(t, claims)
+		}
+	})
+
+	t.Run("should reject an unknown key", func(t *testing.T) {
+		mockKeyRepo := mocks.NewMockIAPIKeyRepository(ctrl)
+		mockKeyRepo.EXPECT().FindAPIKeyByHash(hashToken(key)).Return(nil, errors.New("record not found"))
+
+		srv := NewAPIKeyService(mockKeyRepo, nil)
+		_, err := srv.AuthenticateAPIKey(key)
+		assert.Equal(t, ErrInvalidAPIKey, err)
+	})
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+)
+
+const (
+	// cursorPositionLength is the size of an encoded model.UserCursor:
+	// creation time as Unix seconds (uint64) and nanoseconds (uint32), then
+	// the ID (uint64), all big-endian. Unlike Unix nanoseconds, this covers
+	// every time.Time, including the zero time.
+	cursorPositionLength = 20
+	// cursorFiltersLength is the size of the digest of the filters a cursor
+	// was issued for, which follows the position.
+	cursorFiltersLength = 16
+	cursorPayloadLength = cursorPositionLength + cursorFiltersLength
+)
+
+var (
+	ErrInvalidCursor       = model.NewError(model.ErrValidation, "invalid pagination cursor")
+	ErrCursorFilterChanged = model.NewError(model.ErrValidation, "pagination cursor was issued for different filters")
+)
+
+// CursorCodec turns keyset positions into opaque strings and back. Cursors
+// are signed with HMAC-SHA256 so that clients cannot forge positions, and
+// are bound to the filters of the listing they were issued for, since a
+// position means nothing in another listing.
+type CursorCodec struct {
+	key []byte
+}
+
+func NewCursorCodec(key []byte) *CursorCodec {
+	return &CursorCodec{key: key}
+}
+
+// Encode returns the cursor for the position after cursor in the listing
+// filtered by query.
+func (c *CursorCodec) Encode(cursor model.UserCursor, query model.UserQuery) string {
+	payload := make([]byte, cursorPayloadLength)
+	binary.BigEndian.PutUint64(payload[:8], uint64(cursor.CreatedAt.Unix()))
+	binary.BigEndian.PutUint32(payload[8:12], uint32(cursor.CreatedAt.Nanosecond()))
+	binary.BigEndian.PutUint64(payload[12:cursorPositionLength], uint64(cursor.ID))
+	copy(payload[cursorPositionLength:], filterDigest(query))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+// Decode checks the signature of value and returns the position in it. Any
+// malformed or tampered cursor gives ErrInvalidCursor, and one issued for
+// other filters than those of query gives ErrCursorFilterChanged.
+func (c *CursorCodec) Decode(value string, query model.UserQuery) (model.UserCursor, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(value, ".")
+	if !found {
+		return model.UserCursor{}, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != cursorPayloadLength {
+		return model.UserCursor{}, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return model.UserCursor{}, ErrInvalidCursor
+	}
+	if !hmac.Equal(payload[cursorPositionLength:], filterDigest(query)) {
+		return model.UserCursor{}, ErrCursorFilterChanged
+	}
+
+	return model.UserCursor{
+		CreatedAt: time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), int64(binary.BigEndian.Uint32(payload[8:12]))).UTC(),
+		ID:        uint(binary.BigEndian.Uint64(payload[12:cursorPositionLength])),
+	}, nil
+}
+
+// filterDigest identifies the filters of query. Paging and sorting are left
+// out; keyset listings ignore them.
+func filterDigest(query model.UserQuery) []byte {
+	digest := sha256.New()
+	for _, filter := range []string{query.Name, query.Email, filterTime(query.CreatedAfter), filterTime(query.CreatedBefore)} {
+		// The length keeps the boundaries between the filters.
+		digest.Write(binary.BigEndian.AppendUint32(nil, uint32(len(filter))))
+		digest.Write([]byte(filter))
+	}
+	return digest.Sum(nil)[:cursorFiltersLength]
+}
+
+func filterTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
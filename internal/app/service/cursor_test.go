@@ -0,0 +1,73 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCursors = NewCursorCodec([]byte("cursor secret"))
+
+func TestService_CursorCodec(t *testing.T) {
+	position := model.UserCursor{CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC), ID: 42}
+	filters := model.UserQuery{Name: "jane", Email: "example"}
+
+	t.Run("should round-trip a position", func(t *testing.T) {
+		decoded, err := testCursors.Decode(testCursors.Encode(position, filters), filters)
+		assert.Nil(t, err)
+
+		assert.True(t, position.CreatedAt.Equal(decoded.CreatedAt))
+		assert.Equal(t, position.ID, decoded.ID)
+	})
+
+	t.Run("should round-trip the zero time", func(t *testing.T) {
+		decoded, err := testCursors.Decode(testCursors.Encode(model.UserCursor{ID: 7}, filters), filters)
+		assert.Nil(t, err)
+
+		assert.True(t, decoded.CreatedAt.IsZero())
+		assert.Equal(t, uint(7), decoded.ID)
+	})
+
+	t.Run("should reject a tampered cursor", func(t *testing.T) {
+		cursor := testCursors.Encode(position, filters)
+		payload, signature, _ := strings.Cut(cursor, ".")
+		forged := testCursors.Encode(model.UserCursor{CreatedAt: position.CreatedAt, ID: 1}, filters)
+		forgedPayload, _, _ := strings.Cut(forged, ".")
+
+		for _, value := range []string{
+			forgedPayload + "." + signature,
+			payload,
+			payload + ".",
+			"not a cursor",
+			NewCursorCodec([]byte("other secret")).Encode(position, filters),
+		} {
+			_, err := testCursors.Decode(value, filters)
+			assert.Equal(t, ErrInvalidCursor, err, value)
+		}
+	})
+
+	t.Run("should reject a cursor issued for other filters", func(t *testing.T) {
+		cursor := testCursors.Encode(position, filters)
+		after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		for _, other := range []model.UserQuery{
+			{},
+			{Name: "jane"},
+			{Name: "jan", Email: "e"},
+			{Name: "jane", Email: "example", CreatedAfter: &after},
+		} {
+			_, err := testCursors.Decode(cursor, other)
+			assert.Equal(t, ErrCursorFilterChanged, err, other)
+		}
+	})
+
+	t.Run("should ignore paging and sorting", func(t *testing.T) {
+		cursor := testCursors.Encode(position, filters)
+		_, err := testCursors.Decode(cursor, model.UserQuery{Name: "jane", Email: "example", PerPage: 5, Sort: "name"})
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+)
+
+const (
+	defaultEmailVerificationTTL = 24 * time.Hour
+	defaultResendInterval       = 1 * time.Minute
+)
+
+var (
+	ErrInvalidVerificationToken = model.NewError(model.ErrValidation, "invalid or expired verification token")
+	ErrEmailNotVerified         = model.NewError(model.ErrForbidden, "email address is not verified")
+)
+
+// VerificationSender sends a verification link to a newly created or
+// changed email address.
+type VerificationSender interface {
+	SendVerification(user *model.User) error
+}
+
+type EmailVerificationService struct {
+	users          repository.IRepository
+	tokens         repository.IOneTimeTokenRepository
+	mailer         Mailer
+	verifyURL      string
+	ttl            time.Duration
+	resendInterval time.Duration
+}
+
+// NewEmailVerificationService builds the verification flow. verifyURL is the
+// link sent to users with the token appended to it. resendInterval is the
+// minimum time between two verification emails to the same account.
+func NewEmailVerificationService(users repository.IRepository, tokens repository.IOneTimeTokenRepository, mailer Mailer, verifyURL string, ttl, resendInterval time.Duration) *EmailVerificationService {
+	if ttl <= 0 {
+		ttl = defaultEmailVerificationTTL
+	}
+	if resendInterval <= 0 {
+		resendInterval = defaultResendInterval
+	}
+	return &EmailVerificationService{
+		users:          users,
+		tokens:         tokens,
+		mailer:         mailer,
+		verifyURL:      verifyURL,
+		ttl:            ttl,
+		resendInterval: resendInterval,
+	}
+}
+
+// SendVerification implements VerificationSender. Earlier verification
+// tokens of the user stop working.
+func (s *EmailVerificationService) SendVerification(user *model.User) error {
+	if err := s.tokens.DeleteUserOneTimeTokens(user.ID, model.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = s.tokens.CreateOneTimeToken(&model.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   model.TokenPurposeEmailVerification,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\nPlease confirm your email address with the link below. It expires in %s.\n\n%s%s", user.Name, s.ttl, s.verifyURL, token)
+	return s.mailer.Send(user.Email, "Confirm your email address", body)
+}
+
+// ResendVerification mails a new link to email. Unknown, already verified and
+// recently mailed accounts are skipped silently, so the answer never tells
+// whether an account exists.
+func (s *EmailVerificationService) ResendVerification(email string) error {
+	user, err := s.users.FindUserByEmail(email)
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+
+	latest, err := s.tokens.FindLatestOneTimeToken(user.ID, model.TokenPurposeEmailVerification)
+	if err == nil && time.Since(latest.CreatedAt) < s.resendInterval {
+		return nil
+	}
+
+	return s.SendVerification(user)
+}
+
+func (s *EmailVerificationService) VerifyEmail(token string) error {
+	verification, err := s.tokens.FindOneTimeToken(model.TokenPurposeEmailVerification, hashToken(token))
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	consumed, err := s.tokens.ConsumeOneTimeToken(verification.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidVerificationToken
+	}
+
+	if err := s.users.MarkEmailVerified(verification.UserID); err != nil {
+		return err
+	}
+
+	return s.tokens.DeleteUserOneTimeTokens(verification.UserID, model.TokenPurposeEmailVerification)
+}
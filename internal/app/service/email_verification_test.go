@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+	"golangHexagonal/internal/infrastructure/mail"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_SendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should mail a link with a token whose hash is stored", func(t *testing.T) {
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockTokenRepo.EXPECT().DeleteUserOneTimeTokens(uint(1), model.TokenPurposeEmailVerification).Return(nil)
+
+		var stored *model.OneTimeToken
+		mockTokenRepo.EXPECT().CreateOneTimeToken(gomock.Any()).DoAndReturn(func(token *model.OneTimeToken) error {
+			stored = token
+			return nil
+		})
+
+		srv := NewEmailVerificationService(nil, mockTokenRepo, mailer, "https://app/verify?token=", time.Hour, time.Minute)
+		err := srv.SendVerification(&model.User{ID: 1, Email: "test@gmail.com"})
+		assert.Nil(t, err)
+
+		messages := mailer.Messages()
+		assert.Len(t, messages, 1)
+
+		token := tokenFromLink(t, messages[0].Body, "https://app/verify?token=")
+		assert.Equal(t, hashToken(token), stored.TokenHash)
+		assert.Equal(t, model.TokenPurposeEmailVerification, stored.Purpose)
+	})
+}
+
+func TestService_ResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should skip an account that was mailed recently", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockUserRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{ID: 1, Email: "test@gmail.com"}, nil)
+		mockTokenRepo.EXPECT().FindLatestOneTimeToken(uint(1), model.TokenPurposeEmailVerification).Return(&model.OneTimeToken{CreatedAt: time.Now()}, nil)
+
+		srv := NewEmailVerificationService(mockUserRepo, mockTokenRepo, mailer, "", time.Hour, time.Minute)
+		err := srv.ResendVerification("test@gmail.com")
+		assert.Nil(t, err)
+
+		assert.Empty(t, mailer.Messages())
+	})
+
+	t.Run("should resend once the interval has passed", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockUserRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{ID: 1, Email: "test@gmail.com"}, nil)
+		mockTokenRepo.EXPECT().FindLatestOneTimeToken(uint(1), model.TokenPurposeEmailVerification).Return(&model.OneTimeToken{CreatedAt: time.Now().Add(-time.Hour)}, nil)
+		mockTokenRepo.EXPECT().DeleteUserOneTimeTokens(uint(1), model.TokenPurposeEmailVerification).Return(nil)
+		mockTokenRepo.EXPECT().CreateOneTimeToken(gomock.Any()).Return(nil)
+
+		srv := NewEmailVerificationService(mockUserRepo, mockTokenRepo, mailer, "", time.Hour, time.Minute)
+		err := srv.ResendVerification("test@gmail.com")
+		assert.Nil(t, err)
+
+		assert.Len(t, mailer.Messages(), 1)
+	})
+
+	t.Run("should skip verified and unknown accounts", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockUserRepo.EXPECT().FindUserByEmail("verified@gmail.com").Return(&model.User{ID: 1, EmailVerified: true}, nil)
+		mockUserRepo.EXPECT().FindUserByEmail("unknown@gmail.com").Return(nil, errors.New("record not found"))
+
+		srv := NewEmailVerificationService(mockUserRepo, nil, mailer, "", time.Hour, time.Minute)
+		assert.Nil(t, srv.ResendVerification("verified@gmail.com"))
+		assert.Nil(t, srv.ResendVerification("unknown@gmail.com"))
+
+		assert.Empty(t, mailer.Messages())
+	})
+}
+
+func TestService_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	verification := func() *model.OneTimeToken {
+		return &model.OneTimeToken{
+			ID:        5,
+			UserID:    1,
+			Purpose:   model.TokenPurposeEmailVerification,
+			TokenHash: hashToken("token"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("should mark the email as verified", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposeEmailVerification, hashToken("token")).Return(verification(), nil)
+		mockTokenRepo.EXPECT().ConsumeOneTimeToken(uint(5)).Return(true, nil)
+		mockUserRepo.EXPECT().MarkEmailVerified(uint(1)).Return(nil)
+		mockTokenRepo.EXPECT().DeleteUserOneTimeTokens(uint(1), model.TokenPurposeEmailVerification).Return(nil)
+
+		srv := NewEmailVerificationService(mockUserRepo, mockTokenRepo, nil, "", time.Hour, time.Minute)
+		err := srv.VerifyEmail("token")
+		assert.Nil(t, err)
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		token := verification()
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposeEmailVerification, hashToken("token")).Return(token, nil)
+
+		srv := NewEmailVerificationService(nil, mockTokenRepo, nil, "", time.Hour, time.Minute)
+		err := srv.VerifyEmail("token")
+		assert.Equal(t, ErrInvalidVerificationToken, err)
+	})
+}
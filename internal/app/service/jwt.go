@@ -1,55 +1,153 @@
 package service
 
 import (
+	"errors"
+	"strconv"
 	"time"
 
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+	"golangHexagonal/internal/config"
+
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var jwtSecretKey = []byte("secret")
+const (
+	defaultAccessTokenTTL  = 1 * time.Hour
+	defaultMFAChallengeTTL = 5 * time.Minute
+)
+
+// tokenPurposeMFAChallenge marks a token that only proves the password step
+// of a login and can only be exchanged at the MFA step.
+const tokenPurposeMFAChallenge = "mfa_challenge"
+
+var (
+	ErrTokenMissingID      = errors.New("token has no jti")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrTokenExpired        = errors.New("token is expired")
+	ErrTokenNotYetValid    = errors.New("token is not valid yet")
+	ErrTokenIssuedInFuture = errors.New("token was issued in the future")
+	ErrInvalidIssuer       = errors.New("token issuer does not match")
+	ErrInvalidAudience     = errors.New("token audience does not match")
+	ErrInvalidSubject      = errors.New("token subject does not match")
+	ErrWrongTokenPurpose   = errors.New("token cannot be used for this purpose")
+)
 
 type JWTActions interface {
-	GenerateToken(userID uint) (string, error)
+	GenerateToken(user *model.User, sessionID string) (string, error)
 	VerifyToken(tokenString string) (*Claims, error)
+	RevokeToken(claims *Claims) error
+	JWKS() JWKSet
+	GenerateMFAChallenge(user *model.User) (string, error)
+	VerifyMFAChallenge(tokenString string) (*Claims, error)
 }
 
 type JWTService struct {
-	JWTActions JWTActions
+	JWTActions  JWTActions
+	keys        *KeySet
+	revocations repository.IRevocationRepository
+	issuer      string
+	audience    []string
+	ttl         time.Duration
+	mfaTTL      time.Duration
+	leeway      time.Duration
 }
 
-func NewJWTService() *JWTService {
-	return &JWTService{}
+func NewJWTService(keys *KeySet, cfg config.JWTConfig, revocations repository.IRevocationRepository) *JWTService {
+	return &JWTService{
+		keys:        keys,
+		revocations: revocations,
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		ttl:         cfg.AccessTokenTTL.Or(defaultAccessTokenTTL),
+		mfaTTL:      cfg.MFAChallengeTTL.Or(defaultMFAChallengeTTL),
+		leeway:      cfg.Leeway.Duration(),
+	}
 }
 
+// Claims are the claims of every token the service issues. Purpose is empty
+// for access tokens, which carry the ID of their login session instead.
+//
+// Callers authenticated with an API key get Claims too, with APIKeyID set and
+// access limited to Scopes. They never appear in a token.
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint   `json:"user_id"`
+	Role      string `json:"role"`
+	Purpose   string `json:"purpose,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
+
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// HasScope reports whether the caller may use scope. Access tokens carry
+// every scope.
+func (c *Claims) HasScope(scope string) bool {
+	if c.APIKeyID == 0 {
+		return true
+	}
+	for _, granted := range c.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
 }
 
 // GenerateToken implements handler.JWTActions.
-func (*JWTService) GenerateToken(userID uint) (string, error) {
-	expirationTime := time.Now().Add(1 * time.Hour)
+func (s *JWTService) GenerateToken(user *model.User, sessionID string) (string, error) {
+	return s.generate(user, "", sessionID, s.ttl)
+}
+
+// GenerateMFAChallenge implements handler.JWTActions. The challenge is
+// short-lived and refused by VerifyToken, so it grants no access by itself.
+func (s *JWTService) GenerateMFAChallenge(user *model.User) (string, error) {
+	return s.generate(user, tokenPurposeMFAChallenge, "", s.mfaTTL)
+}
+
+func (s *JWTService) generate(user *model.User, purpose, sessionID string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
 
 	claims := &Claims{
-		UserID: userID,
+		UserID:    user.ID,
+		Role:      user.Role,
+		Purpose:   purpose,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			ID:        jti,
+			Issuer:    s.issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Audience:  s.audience,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecretKey)
-
-	return tokenString, err
+	return s.keys.sign(claims)
 }
 
 // VerifyToken implements handler.JWTActions.
-func (*JWTService) VerifyToken(tokenString string) (*Claims, error) {
+func (s *JWTService) VerifyToken(tokenString string) (*Claims, error) {
+	return s.verify(tokenString, "")
+}
+
+// VerifyMFAChallenge implements handler.JWTActions.
+func (s *JWTService) VerifyMFAChallenge(tokenString string) (*Claims, error) {
+	return s.verify(tokenString, tokenPurposeMFAChallenge)
+}
+
+func (s *JWTService) verify(tokenString, purpose string) (*Claims, error) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecretKey, nil
-	})
+	parser := jwt.NewParser(jwt.WithValidMethods(s.keys.Algorithms()), jwt.WithoutClaimsValidation())
+	token, err := parser.ParseWithClaims(tokenString, claims, s.keys.keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -59,5 +157,90 @@ func (*JWTService) VerifyToken(tokenString string) (*Claims, error) {
 		return nil, jwt.ErrSignatureInvalid
 	}
 
+	if err := s.validateClaims(claims, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != purpose {
+		return nil, ErrWrongTokenPurpose
+	}
+
+	revoked, err := s.revocations.IsTokenRevoked(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
 	return claims, nil
 }
+
+// validateClaims checks the registered claims strictly: exp is required,
+// time-based claims are compared with the configured leeway, and iss, aud,
+// sub and jti must all match what GenerateToken issues.
+func (s *JWTService) validateClaims(claims *Claims, now time.Time) error {
+	if !claims.VerifyExpiresAt(now.Add(-s.leeway), true) {
+		return ErrTokenExpired
+	}
+
+	if !claims.VerifyNotBefore(now.Add(s.leeway), false) {
+		return ErrTokenNotYetValid
+	}
+
+	if !claims.VerifyIssuedAt(now.Add(s.leeway), false) {
+		return ErrTokenIssuedInFuture
+	}
+
+	if claims.Issuer != s.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if !s.verifyAudience(claims) {
+		return ErrInvalidAudience
+	}
+
+	if claims.Subject != strconv.FormatUint(uint64(claims.UserID), 10) {
+		return ErrInvalidSubject
+	}
+
+	if claims.ID == "" {
+		return ErrTokenMissingID
+	}
+
+	return nil
+}
+
+func (s *JWTService) verifyAudience(claims *Claims) bool {
+	if len(s.audience) == 0 {
+		return len(claims.Audience) == 0
+	}
+
+	for _, audience := range s.audience {
+		if claims.VerifyAudience(audience, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokeToken implements handler.JWTActions. The revocation is kept until
+// the token would have expired anyway.
+func (s *JWTService) RevokeToken(claims *Claims) error {
+	if claims.ID == "" {
+		return ErrTokenMissingID
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time.Add(s.leeway)
+	}
+
+	return s.revocations.RevokeToken(claims.ID, expiresAt)
+}
+
+// JWKS implements handler.JWTActions.
+func (s *JWTService) JWKS() JWKSet {
+	return s.keys.JWKS()
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_VerifyToken(t *testing.T) {
+	t.Run("should return claims for a generated token", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, err := srv.GenerateToken(&model.User{ID: 1}, "session")
+		assert.Nil(t, err)
+
+		claims, err := srv.VerifyToken(token)
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint(1), claims.UserID)
+		assert.NotEmpty(t, claims.ID)
+	})
+
+	t.Run("should issue a unique jti per token", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		first, _ := srv.GenerateToken(&model.User{ID: 1}, "session")
+		second, _ := srv.GenerateToken(&model.User{ID: 1}, "session")
+
+		firstClaims, err := srv.VerifyToken(first)
+		assert.Nil(t, err)
+		secondClaims, err := srv.VerifyToken(second)
+		assert.Nil(t, err)
+
+		assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+	})
+
+	t.Run("should reject a revoked token", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, _ := srv.GenerateToken(&model.User{ID: 1}, "session")
+
+		claims, err := srv.VerifyToken(token)
+		assert.Nil(t, err)
+		assert.Nil(t, srv.RevokeToken(claims))
+
+		claims, err = srv.VerifyToken(token)
+		assert.True(t, errors.Is(err, ErrTokenRevoked))
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should set the registered claims", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, _ := srv.GenerateToken(&model.User{ID: 42, Role: model.RoleAdmin}, "session")
+
+		claims, err := srv.VerifyToken(token)
+		assert.Nil(t, err)
+
+		assert.Equal(t, model.RoleAdmin, claims.Role)
+		assert.Equal(t, "test-issuer", claims.Issuer)
+		assert.Equal(t, jwt.ClaimStrings{"test-audience"}, claims.Audience)
+		assert.Equal(t, "42", claims.Subject)
+		assert.NotNil(t, claims.IssuedAt)
+		assert.NotNil(t, claims.NotBefore)
+		assert.NotNil(t, claims.ExpiresAt)
+	})
+
+	t.Run("should reject a token from another issuer", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.Issuer = "someone-else"
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.True(t, errors.Is(err, ErrInvalidIssuer))
+	})
+
+	t.Run("should reject a token for another audience", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.Audience = jwt.ClaimStrings{"other-service"}
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.True(t, errors.Is(err, ErrInvalidAudience))
+	})
+
+	t.Run("should reject a subject that does not match the user", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.Subject = "2"
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.True(t, errors.Is(err, ErrInvalidSubject))
+	})
+
+	t.Run("should reject a token without exp", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.ExpiresAt = nil
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.True(t, errors.Is(err, ErrTokenExpired))
+	})
+
+	t.Run("should accept an expired token within the leeway", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.Nil(t, err)
+	})
+
+	t.Run("should reject an expired token beyond the leeway", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.True(t, errors.Is(err, ErrTokenExpired))
+	})
+
+	t.Run("should reject a token that is not valid yet", func(t *testing.T) {
+		claims := validTestClaims()
+		claims.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Minute))
+
+		_, err := newTestJWTService(t).VerifyToken(signTestClaims(t, claims))
+		assert.True(t, errors.Is(err, ErrTokenNotYetValid))
+	})
+
+	t.Run("should reject an unsigned token", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, validTestClaims())
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		assert.Nil(t, err)
+
+		_, err = newTestJWTService(t).VerifyToken(signed)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should reject a malformed token", func(t *testing.T) {
+		srv := newTestJWTService(t)
+
+		claims, err := srv.VerifyToken("not-a-token")
+		assert.NotNil(t, err)
+		assert.Nil(t, claims)
+	})
+}
+
+func newTestJWTService(t *testing.T) *JWTService {
+	return newJWTServiceFromConfig(t, testJWTConfig())
+}
+
+func testJWTConfig() config.JWTConfig {
+	return config.JWTConfig{
+		ActiveKeyID: "test",
+		Keys:        []config.JWTKey{{ID: "test", Algorithm: "HS256", Secret: testSecret}},
+		Issuer:      "test-issuer",
+		Audience:    []string{"test-audience"},
+		Leeway:      config.Duration(30 * time.Second),
+	}
+}
+
+// signTestClaims signs arbitrary claims with the key of testJWTConfig.
+func signTestClaims(t *testing.T, claims *Claims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString([]byte(testSecret))
+	assert.Nil(t, err)
+	return signed
+}
+
+func validTestClaims() *Claims {
+	now := time.Now()
+	return &Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Issuer:    "test-issuer",
+			Subject:   "1",
+			Audience:  jwt.ClaimStrings{"test-audience"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestService_VerifyMFAChallenge(t *testing.T) {
+	t.Run("should accept a challenge token", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, err := srv.GenerateMFAChallenge(&model.User{ID: 1})
+		assert.Nil(t, err)
+
+		claims, err := srv.VerifyMFAChallenge(token)
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint(1), claims.UserID)
+	})
+
+	t.Run("should not accept a challenge token as an access token", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, _ := srv.GenerateMFAChallenge(&model.User{ID: 1})
+
+		claims, err := srv.VerifyToken(token)
+		assert.True(t, errors.Is(err, ErrWrongTokenPurpose))
+		assert.Nil(t, claims)
+	})
+
+	t.Run("should not accept an access token as a challenge", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, _ := srv.GenerateToken(&model.User{ID: 1}, "session")
+
+		claims, err := srv.VerifyMFAChallenge(token)
+		assert.True(t, errors.Is(err, ErrWrongTokenPurpose))
+		assert.Nil(t, claims)
+	})
+}
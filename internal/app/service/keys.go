@@ -0,0 +1,232 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"golangHexagonal/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// minHMACSecretLength is the shortest HS256 secret accepted, matching the
+// size of the SHA-256 output.
+const minHMACSecretLength = 32
+
+var (
+	ErrUnknownKeyID        = errors.New("unknown signing key id")
+	ErrUnexpectedAlgorithm = errors.New("unexpected signing algorithm")
+)
+
+type signingKey struct {
+	id     string
+	method jwt.SigningMethod
+	sign   crypto.PrivateKey
+	verify crypto.PublicKey
+}
+
+// KeySet holds the keys JWTService signs and verifies tokens with, indexed
+// by their kid header.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// JWK is the public part of a key as published in a JSON Web Key Set.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*signingKey, len(cfg.Keys))}
+
+	for _, keyConfig := range cfg.Keys {
+		if keyConfig.ID == "" {
+			return nil, errors.New("jwt key without kid")
+		}
+		if _, ok := set.keys[keyConfig.ID]; ok {
+			return nil, fmt.Errorf("duplicate jwt key %q", keyConfig.ID)
+		}
+
+		key, err := loadSigningKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", keyConfig.ID, err)
+		}
+		set.keys[key.id] = key
+	}
+
+	active, ok := set.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", cfg.ActiveKeyID)
+	}
+	if active.sign == nil {
+		return nil, fmt.Errorf("active jwt key %q has no private key", cfg.ActiveKeyID)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+// Algorithms returns the algorithms of every configured key.
+func (s *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algorithms []string
+	for _, key := range s.keys {
+		alg := key.method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			algorithms = append(algorithms, alg)
+		}
+	}
+	return algorithms
+}
+
+// sign signs claims with the active key and stamps its kid header.
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.method, claims)
+	token.Header["kid"] = s.active.id
+	return token.SignedString(s.active.sign)
+}
+
+// keyFunc resolves the verification key from the kid header and refuses
+// tokens whose alg differs from the one configured for that key.
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnexpectedAlgorithm
+	}
+
+	return key.verify, nil
+}
+
+// JWKS returns the public keys of the set. Symmetric keys are never
+// published.
+func (s *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range s.keys {
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.id,
+				Algorithm: key.method.Alg(),
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.id,
+				Algorithm: key.method.Alg(),
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	return set
+}
+
+func loadSigningKey(cfg config.JWTKey) (*signingKey, error) {
+	key := &signingKey{id: cfg.ID}
+
+	switch cfg.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		secret := cfg.Secret
+		if cfg.SecretEnv != "" {
+			secret = os.Getenv(cfg.SecretEnv)
+		}
+		if len(secret) < minHMACSecretLength {
+			return nil, fmt.Errorf("HS256 secret must be at least %d bytes", minHMACSecretLength)
+		}
+		key.method = jwt.SigningMethodHS256
+		key.sign = []byte(secret)
+		key.verify = []byte(secret)
+
+	case jwt.SigningMethodRS256.Alg():
+		key.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.sign = private
+			key.verify = &private.PublicKey
+		} else if cfg.PublicKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.verify = public
+		}
+
+	case jwt.SigningMethodEdDSA.Alg():
+		key.method = jwt.SigningMethodEdDSA
+		if cfg.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			edPrivate, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("not an Ed25519 private key")
+			}
+			key.sign = edPrivate
+			key.verify = edPrivate.Public()
+		} else if cfg.PublicKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseEdPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.verify = public
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	if key.verify == nil {
+		return nil, errors.New("no key material configured")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+	"golangHexagonal/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_KeySet(t *testing.T) {
+	t.Run("should sign and verify with RS256", func(t *testing.T) {
+		cfg := config.JWTConfig{
+			ActiveKeyID: "rsa-1",
+			Keys:        []config.JWTKey{{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: writeRSAKey(t)}},
+		}
+		srv := newJWTServiceFromConfig(t, cfg)
+
+		token, err := srv.GenerateToken(&model.User{ID: 1}, "session")
+		assert.Nil(t, err)
+
+		claims, err := srv.VerifyToken(token)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), claims.UserID)
+	})
+
+	t.Run("should sign and verify with EdDSA", func(t *testing.T) {
+		cfg := config.JWTConfig{
+			ActiveKeyID: "ed-1",
+			Keys:        []config.JWTKey{{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: writeEd25519Key(t)}},
+		}
+		srv := newJWTServiceFromConfig(t, cfg)
+
+		token, err := srv.GenerateToken(&model.User{ID: 1}, "session")
+		assert.Nil(t, err)
+
+		claims, err := srv.VerifyToken(token)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), claims.UserID)
+	})
+
+	t.Run("should verify tokens of a rotated-out key", func(t *testing.T) {
+		oldKey := config.JWTKey{ID: "old", Algorithm: "HS256", Secret: testSecret}
+		newKey := config.JWTKey{ID: "new", Algorithm: "EdDSA", PrivateKeyFile: writeEd25519Key(t)}
+
+		before := newJWTServiceFromConfig(t, config.JWTConfig{ActiveKeyID: "old", Keys: []config.JWTKey{oldKey}})
+		token, err := before.GenerateToken(&model.User{ID: 1}, "session")
+		assert.Nil(t, err)
+
+		after := newJWTServiceFromConfig(t, config.JWTConfig{ActiveKeyID: "new", Keys: []config.JWTKey{oldKey, newKey}})
+		claims, err := after.VerifyToken(token)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), claims.UserID)
+	})
+
+	t.Run("should reject tokens with an unknown kid", func(t *testing.T) {
+		srv := newTestJWTService(t)
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 1}).SignedString([]byte(testSecret))
+		assert.Nil(t, err)
+
+		_, err = srv.VerifyToken(token)
+		assert.True(t, errors.Is(err, ErrUnknownKeyID))
+	})
+
+	t.Run("should reject an algorithm that does not match the kid", func(t *testing.T) {
+		rsaPath := writeRSAKey(t)
+		srv := newJWTServiceFromConfig(t, config.JWTConfig{
+			ActiveKeyID: "hs",
+			Keys: []config.JWTKey{
+				{ID: "hs", Algorithm: "HS256", Secret: testSecret},
+				{ID: "rsa", Algorithm: "RS256", PrivateKeyFile: rsaPath},
+			},
+		})
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 1})
+		token.Header["kid"] = "rsa"
+		signed, err := token.SignedString([]byte(testSecret))
+		assert.Nil(t, err)
+
+		_, err = srv.VerifyToken(signed)
+		assert.True(t, errors.Is(err, ErrUnexpectedAlgorithm))
+	})
+
+	t.Run("should refuse short HS256 secrets", func(t *testing.T) {
+		_, err := NewKeySet(config.JWTConfig{
+			ActiveKeyID: "hs",
+			Keys:        []config.JWTKey{{ID: "hs", Algorithm: "HS256", Secret: "secret"}},
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should read HS256 secrets from the environment", func(t *testing.T) {
+		t.Setenv("TEST_JWT_SECRET", testSecret)
+
+		_, err := NewKeySet(config.JWTConfig{
+			ActiveKeyID: "hs",
+			Keys:        []config.JWTKey{{ID: "hs", Algorithm: "HS256", SecretEnv: "TEST_JWT_SECRET"}},
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should publish only asymmetric keys in the JWKS", func(t *testing.T) {
+		keys, err := NewKeySet(config.JWTConfig{
+			ActiveKeyID: "hs",
+			Keys: []config.JWTKey{
+				{ID: "hs", Algorithm: "HS256", Secret: testSecret},
+				{ID: "rsa", Algorithm: "RS256", PrivateKeyFile: writeRSAKey(t)},
+				{ID: "ed", Algorithm: "EdDSA", PrivateKeyFile: writeEd25519Key(t)},
+			},
+		})
+		assert.Nil(t, err)
+
+		jwks := keys.JWKS()
+		assert.Len(t, jwks.Keys, 2)
+		for _, key := range jwks.Keys {
+			assert.NotEqual(t, "hs", key.KeyID)
+			assert.Equal(t, "sig", key.Use)
+		}
+	})
+}
+
+func newJWTServiceFromConfig(t *testing.T, cfg config.JWTConfig) *JWTService {
+	keys, err := NewKeySet(cfg)
+	assert.Nil(t, err)
+
+	return NewJWTService(keys, cfg, repository.NewMemoryRevocationRepository())
+}
+
+func writeRSAKey(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	der := x509.MarshalPKCS1PrivateKey(key)
+	return writePEM(t, "RSA PRIVATE KEY", der)
+}
+
+func writeEd25519Key(t *testing.T) string {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	return writePEM(t, "PRIVATE KEY", der)
+}
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	assert.Nil(t, err)
+	return path
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golangHexagonal/internal/app/repository"
+	"golangHexagonal/internal/config"
+)
+
+const (
+	defaultLoginMaxFailures   = 5
+	defaultLoginIPMaxFailures = 50
+	defaultLoginLockout       = 15 * time.Minute
+	defaultLoginBaseDelay     = 1 * time.Second
+
+	// challengeMaxFailures is how many wrong codes an MFA login challenge
+	// takes before it is revoked.
+	challengeMaxFailures = 3
+)
+
+// LoginThrottle slows down and then locks out password guessing. Failures
+// are counted per email address, whether or not an account exists for it,
+// and per client IP, so that the throttled response never reveals which
+// emails are registered.
+//
+// Every failure on an email doubles the wait before the next attempt, from
+// baseDelay up to the lockout. After maxFailures the email is locked out for
+// the lockout duration. Client IPs are only locked out, after ipMaxFailures,
+// because many users can share one address.
+//
+// Second-factor codes go through the same throttle, counted per user instead
+// of per email. Wrong codes are also counted per MFA login challenge, so that
+// a challenge can be revoked before the account is locked out.
+type LoginThrottle struct {
+	attempts      repository.ILoginAttemptRepository
+	users         repository.IRepository
+	maxFailures   int
+	ipMaxFailures int
+	lockout       time.Duration
+	baseDelay     time.Duration
+	now           func() time.Time
+}
+
+func NewLoginThrottle(attempts repository.ILoginAttemptRepository, users repository.IRepository, cfg config.AuthConfig) *LoginThrottle {
+	return &LoginThrottle{
+		attempts:      attempts,
+		users:         users,
+		maxFailures:   intOr(cfg.LoginMaxFailures, defaultLoginMaxFailures),
+		ipMaxFailures: intOr(cfg.LoginIPMaxFailures, defaultLoginIPMaxFailures),
+		lockout:       cfg.LoginLockout.Or(defaultLoginLockout),
+		baseDelay:     cfg.LoginBaseDelay.Or(defaultLoginBaseDelay),
+		now:           time.Now,
+	}
+}
+
+// AllowLogin returns how long the caller must wait before trying email from
+// ip again, or zero when the attempt may go ahead.
+func (t *LoginThrottle) AllowLogin(email, ip string) (time.Duration, error) {
+	return t.allow(emailKey(email), ip)
+}
+
+// RecordLoginFailure counts a failed attempt and locks the email or IP out
+// once it reaches its threshold.
+func (t *LoginThrottle) RecordLoginFailure(email, ip string) error {
+	return t.recordFailures(emailKey(email), ip)
+}
+
+// RecordLoginSuccess clears the failures of email. The IP counter is kept so
+// that logging into one account does not reset guessing against others.
+func (t *LoginThrottle) RecordLoginSuccess(email string) error {
+	return t.attempts.ResetLoginAttempts(emailKey(email))
+}
+
+// AllowMFA is AllowLogin for a second-factor code entered by the user.
+func (t *LoginThrottle) AllowMFA(userID uint, ip string) (time.Duration, error) {
+	return t.allow(mfaKey(userID), ip)
+}
+
+// RecordMFAFailure is RecordLoginFailure for a wrong second-factor code.
+func (t *LoginThrottle) RecordMFAFailure(userID uint, ip string) error {
+	return t.recordFailures(mfaKey(userID), ip)
+}
+
+// RecordMFASuccess is RecordLoginSuccess for a correct second-factor code.
+func (t *LoginThrottle) RecordMFASuccess(userID uint) error {
+	return t.attempts.ResetLoginAttempts(mfaKey(userID))
+}
+
+// RecordChallengeFailure counts a wrong code against the MFA login challenge
+// with challengeID and reports whether it has used up its attempts.
+func (t *LoginThrottle) RecordChallengeFailure(challengeID string) (bool, error) {
+	attempt, err := t.attempts.RecordLoginFailure(challengeKey(challengeID), t.now(), t.lockout)
+	if err != nil {
+		return false, err
+	}
+	return attempt.Failures >= challengeMaxFailures, nil
+}
+
+// UnlockAccount lifts the lockouts of the user's email address and second
+// factor.
+func (t *LoginThrottle) UnlockAccount(userID uint) error {
+	user, err := t.users.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := t.attempts.ResetLoginAttempts(emailKey(user.Email)); err != nil {
+		return err
+	}
+	return t.attempts.ResetLoginAttempts(mfaKey(userID))
+}
+
+// PurgeLoginAttempts drops counters that can no longer delay or lock anyone
+// out. It is meant to run with StartPurger.
+func (t *LoginThrottle) PurgeLoginAttempts(now time.Time) (int64, error) {
+	return t.attempts.PurgeLoginAttempts(now.Add(-t.lockout), now)
+}
+
+// allow returns the longer of the waits of the account key and of ip.
+func (t *LoginThrottle) allow(key, ip string) (time.Duration, error) {
+	now := t.now()
+
+	keyWait, err := t.wait(key, now, true)
+	if err != nil {
+		return 0, err
+	}
+
+	ipWait, err := t.wait(ipKey(ip), now, false)
+	if err != nil {
+		return 0, err
+	}
+
+	if ipWait > keyWait {
+		return ipWait, nil
+	}
+	return keyWait, nil
+}
+
+// recordFailures counts a failure against the account key and against ip.
+func (t *LoginThrottle) recordFailures(key, ip string) error {
+	now := t.now()
+
+	if err := t.recordFailure(key, t.maxFailures, now); err != nil {
+		return err
+	}
+	return t.recordFailure(ipKey(ip), t.ipMaxFailures, now)
+}
+
+func (t *LoginThrottle) wait(key string, now time.Time, progressive bool) (time.Duration, error) {
+	attempt, err := t.attempts.FindLoginAttempt(key)
+	if err != nil || attempt == nil {
+		return 0, err
+	}
+
+	if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+		return attempt.LockedUntil.Sub(now), nil
+	}
+
+	if !progressive || now.Sub(attempt.LastFailureAt) > t.lockout {
+		return 0, nil
+	}
+
+	next := attempt.LastFailureAt.Add(t.delay(attempt.Failures))
+	if now.Before(next) {
+		return next.Sub(now), nil
+	}
+	return 0, nil
+}
+
+func (t *LoginThrottle) recordFailure(key string, maxFailures int, now time.Time) error {
+	attempt, err := t.attempts.RecordLoginFailure(key, now, t.lockout)
+	if err != nil {
+		return err
+	}
+
+	if attempt.Failures >= maxFailures {
+		return t.attempts.LockLoginAttempt(key, now.Add(t.lockout))
+	}
+	return nil
+}
+
+// delay is baseDelay doubled for every failure after the first, capped at
+// the lockout duration.
+func (t *LoginThrottle) delay(failures int) time.Duration {
+	delay := t.baseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.lockout {
+			return t.lockout
+		}
+	}
+	return delay
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func mfaKey(userID uint) string {
+	return "mfa:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+func challengeKey(challengeID string) string {
+	return "challenge:" + challengeID
+}
+
+func intOr(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+	"golangHexagonal/internal/config"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_AllowLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+
+	newThrottle := func(repo *mocks.MockILoginAttemptRepository) *LoginThrottle {
+		throttle := NewLoginThrottle(repo, nil, config.AuthConfig{})
+		throttle.now = func() time.Time { return now }
+		return throttle
+	}
+
+	t.Run("should allow an email without failures", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().FindLoginAttempt("email:test@gmail.com").Return(nil, nil)
+		mockRepo.EXPECT().FindLoginAttempt("ip:10.0.0.1").Return(nil, nil)
+
+		wait, err := newThrottle(mockRepo).AllowLogin("Test@Gmail.com ", "10.0.0.1")
+		assert.Nil(t, err)
+
+		assert.Zero(t, wait)
+	})
+
+	t.Run("should double the delay with every failure", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().FindLoginAttempt("email:test@gmail.com").Return(&model.LoginAttempt{Failures: 3, LastFailureAt: now}, nil)
+		mockRepo.EXPECT().FindLoginAttempt("ip:10.0.0.1").Return(nil, nil)
+
+		wait, err := newThrottle(mockRepo).AllowLogin("test@gmail.com", "10.0.0.1")
+		assert.Nil(t, err)
+
+		assert.Equal(t, 4*time.Second, wait)
+	})
+
+	t.Run("should report the remaining lockout", func(t *testing.T) {
+		lockedUntil := now.Add(10 * time.Minute)
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().FindLoginAttempt("email:test@gmail.com").Return(nil, nil)
+		mockRepo.EXPECT().FindLoginAttempt("ip:10.0.0.1").Return(&model.LoginAttempt{Failures: 50, LastFailureAt: now, LockedUntil: &lockedUntil}, nil)
+
+		wait, err := newThrottle(mockRepo).AllowLogin("test@gmail.com", "10.0.0.1")
+		assert.Nil(t, err)
+
+		assert.Equal(t, 10*time.Minute, wait)
+	})
+
+	t.Run("should not delay an ip that is not locked out", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().FindLoginAttempt("email:test@gmail.com").Return(nil, nil)
+		mockRepo.EXPECT().FindLoginAttempt("ip:10.0.0.1").Return(&model.LoginAttempt{Failures: 10, LastFailureAt: now}, nil)
+
+		wait, err := newThrottle(mockRepo).AllowLogin("test@gmail.com", "10.0.0.1")
+		assert.Nil(t, err)
+
+		assert.Zero(t, wait)
+	})
+}
+
+func TestService_RecordLoginFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+
+	t.Run("should lock the email out at the threshold", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().RecordLoginFailure("email:test@gmail.com", now, defaultLoginLockout).Return(&model.LoginAttempt{Failures: 5}, nil)
+		mockRepo.EXPECT().LockLoginAttempt("email:test@gmail.com", now.Add(defaultLoginLockout)).Return(nil)
+		mockRepo.EXPECT().RecordLoginFailure("ip:10.0.0.1", now, defaultLoginLockout).Return(&model.LoginAttempt{Failures: 5}, nil)
+
+		throttle := NewLoginThrottle(mockRepo, nil, config.AuthConfig{})
+		throttle.now = func() time.Time { return now }
+
+		assert.Nil(t, throttle.RecordLoginFailure("test@gmail.com", "10.0.0.1"))
+	})
+}
+
+func TestService_UnlockAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should clear the failures of the user's email and second factor", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockUserRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Email: "Test@gmail.com"}, nil)
+		mockRepo.EXPECT().ResetLoginAttempts("email:test@gmail.com").Return(nil)
+		mockRepo.EXPECT().ResetLoginAttempts("mfa:1").Return(nil)
+
+		throttle := NewLoginThrottle(mockRepo, mockUserRepo, config.AuthConfig{})
+		assert.Nil(t, throttle.UnlockAccount(1))
+	})
+}
+
+func TestService_MFAThrottle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+
+	t.Run("should delay codes of a user with failures", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().FindLoginAttempt("mfa:1").Return(&model.LoginAttempt{Failures: 2, LastFailureAt: now}, nil)
+		mockRepo.EXPECT().FindLoginAttempt("ip:10.0.0.1").Return(nil, nil)
+
+		throttle := NewLoginThrottle(mockRepo, nil, config.AuthConfig{})
+		throttle.now = func() time.Time { return now }
+
+		wait, err := throttle.AllowMFA(1, "10.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, 2*time.Second, wait)
+	})
+
+	t.Run("should count a wrong code against the user and the ip", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().RecordLoginFailure("mfa:1", now, defaultLoginLockout).Return(&model.LoginAttempt{Failures: 1}, nil)
+		mockRepo.EXPECT().RecordLoginFailure("ip:10.0.0.1", now, defaultLoginLockout).Return(&model.LoginAttempt{Failures: 1}, nil)
+
+		throttle := NewLoginThrottle(mockRepo, nil, config.AuthConfig{})
+		throttle.now = func() time.Time { return now }
+
+		assert.Nil(t, throttle.RecordMFAFailure(1, "10.0.0.1"))
+	})
+
+	t.Run("should report a challenge as spent after too many wrong codes", func(t *testing.T) {
+		mockRepo := mocks.NewMockILoginAttemptRepository(ctrl)
+		mockRepo.EXPECT().RecordLoginFailure("challenge:jti", now, defaultLoginLockout).Return(&model.LoginAttempt{Failures: challengeMaxFailures - 1}, nil)
+		mockRepo.EXPECT().RecordLoginFailure("challenge:jti", now, defaultLoginLockout).Return(&model.LoginAttempt{Failures: challengeMaxFailures}, nil)
+
+		throttle := NewLoginThrottle(mockRepo, nil, config.AuthConfig{})
+		throttle.now = func() time.Time { return now }
+
+		spent, err := throttle.RecordChallengeFailure("jti")
+		assert.Nil(t, err)
+		assert.False(t, spent)
+
+		spent, err = throttle.RecordChallengeFailure("jti")
+		assert.Nil(t, err)
+		assert.True(t, spent)
+	})
+}
@@ -0,0 +1,6 @@
+package service
+
+// Mailer sends plain-text email. Adapters live in infrastructure/mail.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
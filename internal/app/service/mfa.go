@@ -0,0 +1,197 @@
+package service
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// recoveryCodeAlphabet leaves out characters that are easy to misread.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	ErrMFAAlreadyEnabled = model.NewError(model.ErrConflict, "two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = model.NewError(model.ErrConflict, "two-factor authentication enrollment has not been started")
+	ErrMFANotEnabled     = model.NewError(model.ErrConflict, "two-factor authentication is not enabled")
+	ErrInvalidMFACode    = model.NewError(model.ErrValidation, "invalid two-factor authentication code")
+)
+
+type MFAService struct {
+	users         repository.IRepository
+	recoveryCodes repository.IRecoveryCodeRepository
+	issuer        string
+	now           func() time.Time
+}
+
+// NewMFAService builds the TOTP flow. issuer is the account label shown in
+// authenticator apps.
+func NewMFAService(users repository.IRepository, recoveryCodes repository.IRecoveryCodeRepository, issuer string) *MFAService {
+	return &MFAService{users: users, recoveryCodes: recoveryCodes, issuer: issuer, now: time.Now}
+}
+
+// BeginEnrollment stores a new TOTP secret for the user and returns what the
+// authenticator app needs. MFA is not enforced until ConfirmEnrollment
+// succeeds, and starting again replaces an unconfirmed secret.
+func (s *MFAService) BeginEnrollment(userID uint) (*model.MFAEnrollment, error) {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.UpdateMFA(user.ID, secret, false); err != nil {
+		return nil, err
+	}
+
+	return &model.MFAEnrollment{Secret: secret, URI: totpURI(s.issuer, user.Email, secret)}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user proves their authenticator
+// works with a first code. It returns the recovery codes in clear text; they
+// are never shown again.
+func (s *MFAService) ConfirmEnrollment(userID uint, code string) ([]string, error) {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	accepted, err := s.acceptTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, err := s.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.UpdateMFA(user.ID, user.MFASecret, true); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableMFA turns MFA off after checking a TOTP or recovery code, and drops
+// the secret and the remaining recovery codes.
+func (s *MFAService) DisableMFA(userID uint, code string) error {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if err := s.VerifyMFA(user, code); err != nil {
+		return err
+	}
+
+	if err := s.users.UpdateMFA(user.ID, "", false); err != nil {
+		return err
+	}
+
+	return s.recoveryCodes.DeleteRecoveryCodes(user.ID)
+}
+
+// VerifyMFA accepts either a current TOTP code that was not used before or
+// an unused recovery code, which is consumed.
+func (s *MFAService) VerifyMFA(user *model.User, code string) error {
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	accepted, err := s.acceptTOTP(user, code)
+	if err != nil {
+		return err
+	}
+	if accepted {
+		return nil
+	}
+
+	consumed, err := s.recoveryCodes.ConsumeRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// acceptTOTP checks a TOTP code and records its time step as used. Of two
+// requests racing with the same code, only one is accepted.
+func (s *MFAService) acceptTOTP(user *model.User, code string) (bool, error) {
+	step, ok := validateTOTP(user.MFASecret, code, s.now(), user.MFALastStep)
+	if !ok {
+		return false, nil
+	}
+	return s.users.RecordMFAStep(user.ID, step)
+}
+
+func (s *MFAService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]*model.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = &model.RecoveryCode{UserID: userID, CodeHash: hashToken(normalizeRecoveryCode(code))}
+	}
+
+	if err := s.recoveryCodes.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns a code formatted as "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	var code strings.Builder
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			code.WriteByte('-')
+		}
+		index, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(recoveryCodeAlphabet[index.Int64()])
+	}
+	return code.String(), nil
+}
+
+// normalizeRecoveryCode ignores case, dashes and spaces so that codes typed
+// by hand still match.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_BeginEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should store a pending secret", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Email: "test@gmail.com"}, nil)
+		mockRepo.EXPECT().UpdateMFA(uint(1), gomock.Any(), false).Return(nil)
+
+		srv := NewMFAService(mockRepo, nil, "golang-hexagonal")
+		enrollment, err := srv.BeginEnrollment(1)
+		assert.Nil(t, err)
+
+		assert.NotEmpty(t, enrollment.Secret)
+		assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	})
+
+	t.Run("should refuse when mfa is already enabled", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, MFAEnabled: true}, nil)
+
+		srv := NewMFAService(mockRepo, nil, "golang-hexagonal")
+		enrollment, err := srv.BeginEnrollment(1)
+		assert.Equal(t, ErrMFAAlreadyEnabled, err)
+
+		assert.Nil(t, enrollment)
+	})
+}
+
+func TestService_ConfirmEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(59, 0)
+
+	t.Run("should enable mfa and return hashed recovery codes", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockCodeRepo := mocks.NewMockIRecoveryCodeRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, MFASecret: rfc6238Secret}, nil)
+
+		var stored []*model.RecoveryCode
+		mockCodeRepo.EXPECT().ReplaceRecoveryCodes(uint(1), gomock.Any()).DoAndReturn(func(userID uint, codes []*model.RecoveryCode) error {
+			stored = codes
+			return nil
+		})
+		mockRepo.EXPECT().RecordMFAStep(uint(1), uint64(1)).Return(true, nil)
+		mockRepo.EXPECT().UpdateMFA(uint(1), rfc6238Secret, true).Return(nil)
+
+		srv := NewMFAService(mockRepo, mockCodeRepo, "golang-hexagonal")
+		srv.now = func() time.Time { return now }
+
+		codes, err := srv.ConfirmEnrollment(1, "287082")
+		assert.Nil(t, err)
+
+		assert.Len(t, codes, recoveryCodeCount)
+		assert.Len(t, stored, recoveryCodeCount)
+		for i, code := range codes {
+			assert.Equal(t, hashToken(normalizeRecoveryCode(code)), stored[i].CodeHash)
+		}
+	})
+
+	t.Run("should reject a wrong code", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, MFASecret: rfc6238Secret}, nil)
+
+		srv := NewMFAService(mockRepo, nil, "golang-hexagonal")
+		srv.now = func() time.Time { return now }
+
+		codes, err := srv.ConfirmEnrollment(1, "000000")
+		assert.Equal(t, ErrInvalidMFACode, err)
+
+		assert.Nil(t, codes)
+	})
+
+	t.Run("should refuse without a pending secret", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1}, nil)
+
+		srv := NewMFAService(mockRepo, nil, "golang-hexagonal")
+		_, err := srv.ConfirmEnrollment(1, "287082")
+		assert.Equal(t, ErrMFANotEnrolled, err)
+	})
+}
+
+func TestService_VerifyMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &model.User{ID: 1, MFASecret: rfc6238Secret, MFAEnabled: true}
+
+	t.Run("should accept a totp code and record its step", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().RecordMFAStep(uint(1), uint64(1)).Return(true, nil)
+
+		srv := NewMFAService(mockRepo, nil, "golang-hexagonal")
+		srv.now = func() time.Time { return time.Unix(59, 0) }
+
+		assert.Nil(t, srv.VerifyMFA(user, "287082"))
+	})
+
+	t.Run("should reject a replayed totp code", func(t *testing.T) {
+		mockCodeRepo := mocks.NewMockIRecoveryCodeRepository(ctrl)
+		mockCodeRepo.EXPECT().ConsumeRecoveryCode(uint(1), gomock.Any()).Return(false, nil)
+
+		used := *user
+		used.MFALastStep = 1
+
+		srv := NewMFAService(nil, mockCodeRepo, "golang-hexagonal")
+		srv.now = func() time.Time { return time.Unix(59, 0) }
+
+		assert.Equal(t, ErrInvalidMFACode, srv.VerifyMFA(&used, "287082"))
+	})
+
+	t.Run("should reject a totp code another request used first", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().RecordMFAStep(uint(1), uint64(1)).Return(false, nil)
+		mockCodeRepo := mocks.NewMockIRecoveryCodeRepository(ctrl)
+		mockCodeRepo.EXPECT().ConsumeRecoveryCode(uint(1), gomock.Any()).Return(false, nil)
+
+		srv := NewMFAService(mockRepo, mockCodeRepo, "golang-hexagonal")
+		srv.now = func() time.Time { return time.Unix(59, 0) }
+
+		assert.Equal(t, ErrInvalidMFACode, srv.VerifyMFA(user, "287082"))
+	})
+
+	t.Run("should consume a recovery code typed in any case", func(t *testing.T) {
+		mockCodeRepo := mocks.NewMockIRecoveryCodeRepository(ctrl)
+		mockCodeRepo.EXPECT().ConsumeRecoveryCode(uint(1), hashToken("abcdefghjk")).Return(true, nil)
+
+		srv := NewMFAService(nil, mockCodeRepo, "golang-hexagonal")
+		assert.Nil(t, srv.VerifyMFA(user, "ABCDE-FGHJK"))
+	})
+
+	t.Run("should reject a used recovery code", func(t *testing.T) {
+		mockCodeRepo := mocks.NewMockIRecoveryCodeRepository(ctrl)
+		mockCodeRepo.EXPECT().ConsumeRecoveryCode(uint(1), hashToken("abcdefghjk")).Return(false, nil)
+
+		srv := NewMFAService(nil, mockCodeRepo, "golang-hexagonal")
+		assert.Equal(t, ErrInvalidMFACode, srv.VerifyMFA(user, "abcde-fghjk"))
+	})
+}
+
+func TestService_DisableMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should clear the secret and recovery codes", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockCodeRepo := mocks.NewMockIRecoveryCodeRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, MFASecret: rfc6238Secret, MFAEnabled: true}, nil)
+		mockRepo.EXPECT().RecordMFAStep(uint(1), uint64(1)).Return(true, nil)
+		mockRepo.EXPECT().UpdateMFA(uint(1), "", false).Return(nil)
+		mockCodeRepo.EXPECT().DeleteRecoveryCodes(uint(1)).Return(nil)
+
+		srv := NewMFAService(mockRepo, mockCodeRepo, "golang-hexagonal")
+		srv.now = func() time.Time { return time.Unix(59, 0) }
+
+		assert.Nil(t, srv.DisableMFA(1, "287082"))
+	})
+
+	t.Run("should refuse when mfa is not enabled", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1}, nil)
+
+		srv := NewMFAService(mockRepo, nil, "golang-hexagonal")
+		assert.Equal(t, ErrMFANotEnabled, srv.DisableMFA(1, "287082"))
+	})
+}
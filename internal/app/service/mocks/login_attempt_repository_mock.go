@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/login_attempt.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILoginAttemptRepository is a mock of ILoginAttemptRepository interface.
+type MockILoginAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoginAttemptRepositoryMockRecorder
+}
+
+// MockILoginAttemptRepositoryMockRecorder is the mock recorder for MockILoginAttemptRepository.
+type MockILoginAttemptRepositoryMockRecorder struct {
+	mock *MockILoginAttemptRepository
+}
+
+// NewMockILoginAttemptRepository creates a new mock instance.
+func NewMockILoginAttemptRepository(ctrl *gomock.Controller) *MockILoginAttemptRepository {
+	mock := &MockILoginAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockILoginAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoginAttemptRepository) EXPECT() *MockILoginAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindLoginAttempt mocks base method.
+func (m *MockILoginAttemptRepository) FindLoginAttempt(key string) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginAttempt", key)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginAttempt indicates an expected call of FindLoginAttempt.
+func (mr *MockILoginAttemptRepositoryMockRecorder) FindLoginAttempt(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginAttempt", reflect.TypeOf((*MockILoginAttemptRepository)(nil).FindLoginAttempt), key)
+}
+
+// LockLoginAttempt mocks base method.
+func (m *MockILoginAttemptRepository) LockLoginAttempt(key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLoginAttempt", key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLoginAttempt indicates an expected call of LockLoginAttempt.
+func (mr *MockILoginAttemptRepositoryMockRecorder) LockLoginAttempt(key, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLoginAttempt", reflect.TypeOf((*MockILoginAttemptRepository)(nil).LockLoginAttempt), key, until)
+}
+
+// PurgeLoginAttempts mocks base method.
+func (m *MockILoginAttemptRepository) PurgeLoginAttempts(before, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeLoginAttempts", before, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeLoginAttempts indicates an expected call of PurgeLoginAttempts.
+func (mr *MockILoginAttemptRepositoryMockRecorder) PurgeLoginAttempts(before, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeLoginAttempts", reflect.TypeOf((*MockILoginAttemptRepository)(nil).PurgeLoginAttempts), before, now)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockILoginAttemptRepository) RecordLoginFailure(key string, now time.Time, window time.Duration) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", key, now, window)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockILoginAttemptRepositoryMockRecorder) RecordLoginFailure(key, now, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockILoginAttemptRepository)(nil).RecordLoginFailure), key, now, window)
+}
+
+// ResetLoginAttempts mocks base method.
+func (m *MockILoginAttemptRepository) ResetLoginAttempts(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginAttempts", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginAttempts indicates an expected call of ResetLoginAttempts.
+func (mr *MockILoginAttemptRepositoryMockRecorder) ResetLoginAttempts(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginAttempts", reflect.TypeOf((*MockILoginAttemptRepository)(nil).ResetLoginAttempts), key)
+}
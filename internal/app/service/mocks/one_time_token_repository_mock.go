@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/one_time_token.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIOneTimeTokenRepository is a mock of IOneTimeTokenRepository interface.
+type MockIOneTimeTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOneTimeTokenRepositoryMockRecorder
+}
+
+// MockIOneTimeTokenRepositoryMockRecorder is the mock recorder for MockIOneTimeTokenRepository.
+type MockIOneTimeTokenRepositoryMockRecorder struct {
+	mock *MockIOneTimeTokenRepository
+}
+
+// NewMockIOneTimeTokenRepository creates a new mock instance.
+func NewMockIOneTimeTokenRepository(ctrl *gomock.Controller) *MockIOneTimeTokenRepository {
+	mock := &MockIOneTimeTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockIOneTimeTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOneTimeTokenRepository) EXPECT() *MockIOneTimeTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeOneTimeToken mocks base method.
+func (m *MockIOneTimeTokenRepository) ConsumeOneTimeToken(id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOneTimeToken", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOneTimeToken indicates an expected call of ConsumeOneTimeToken.
+func (mr *MockIOneTimeTokenRepositoryMockRecorder) ConsumeOneTimeToken(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOneTimeToken", reflect.TypeOf((*MockIOneTimeTokenRepository)(nil).ConsumeOneTimeToken), id)
+}
+
+// CreateOneTimeToken mocks base method.
+func (m *MockIOneTimeTokenRepository) CreateOneTimeToken(token *model.OneTimeToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOneTimeToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOneTimeToken indicates an expected call of CreateOneTimeToken.
+func (mr *MockIOneTimeTokenRepositoryMockRecorder) CreateOneTimeToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOneTimeToken", reflect.TypeOf((*MockIOneTimeTokenRepository)(nil).CreateOneTimeToken), token)
+}
+
+// DeleteUserOneTimeTokens mocks base method.
+func (m *MockIOneTimeTokenRepository) DeleteUserOneTimeTokens(userID uint, purpose string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserOneTimeTokens", userID, purpose)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserOneTimeTokens indicates an expected call of DeleteUserOneTimeTokens.
+func (mr *MockIOneTimeTokenRepositoryMockRecorder) DeleteUserOneTimeTokens(userID, purpose interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserOneTimeTokens", reflect.TypeOf((*MockIOneTimeTokenRepository)(nil).DeleteUserOneTimeTokens), userID, purpose)
+}
+
+// FindLatestOneTimeToken mocks base method.
+func (m *MockIOneTimeTokenRepository) FindLatestOneTimeToken(userID uint, purpose string) (*model.OneTimeToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatestOneTimeToken", userID, purpose)
+	ret0, _ := ret[0].(*model.OneTimeToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatestOneTimeToken indicates an expected call of FindLatestOneTimeToken.
+func (mr *MockIOneTimeTokenRepositoryMockRecorder) FindLatestOneTimeToken(userID, purpose interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatestOneTimeToken", reflect.TypeOf((*MockIOneTimeTokenRepository)(nil).FindLatestOneTimeToken), userID, purpose)
+}
+
+// FindOneTimeToken mocks base method.
+func (m *MockIOneTimeTokenRepository) FindOneTimeToken(purpose, hash string) (*model.OneTimeToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneTimeToken", purpose, hash)
+	ret0, _ := ret[0].(*model.OneTimeToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneTimeToken indicates an expected call of FindOneTimeToken.
+func (mr *MockIOneTimeTokenRepositoryMockRecorder) FindOneTimeToken(purpose, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneTimeToken", reflect.TypeOf((*MockIOneTimeTokenRepository)(nil).FindOneTimeToken), purpose, hash)
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/recovery_code.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRecoveryCodeRepository is a mock of IRecoveryCodeRepository interface.
+type MockIRecoveryCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRecoveryCodeRepositoryMockRecorder
+}
+
+// MockIRecoveryCodeRepositoryMockRecorder is the mock recorder for MockIRecoveryCodeRepository.
+type MockIRecoveryCodeRepositoryMockRecorder struct {
+	mock *MockIRecoveryCodeRepository
+}
+
+// NewMockIRecoveryCodeRepository creates a new mock instance.
+func NewMockIRecoveryCodeRepository(ctrl *gomock.Controller) *MockIRecoveryCodeRepository {
+	mock := &MockIRecoveryCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockIRecoveryCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRecoveryCodeRepository) EXPECT() *MockIRecoveryCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeRecoveryCode mocks base method.
+func (m *MockIRecoveryCodeRepository) ConsumeRecoveryCode(userID uint, hash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", userID, hash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockIRecoveryCodeRepositoryMockRecorder) ConsumeRecoveryCode(userID, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockIRecoveryCodeRepository)(nil).ConsumeRecoveryCode), userID, hash)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockIRecoveryCodeRepository) DeleteRecoveryCodes(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockIRecoveryCodeRepositoryMockRecorder) DeleteRecoveryCodes(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockIRecoveryCodeRepository)(nil).DeleteRecoveryCodes), userID)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockIRecoveryCodeRepository) ReplaceRecoveryCodes(userID uint, codes []*model.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", userID, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockIRecoveryCodeRepositoryMockRecorder) ReplaceRecoveryCodes(userID, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockIRecoveryCodeRepository)(nil).ReplaceRecoveryCodes), userID, codes)
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/refresh_token.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRefreshTokenRepository is a mock of IRefreshTokenRepository interface.
+type MockIRefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRefreshTokenRepositoryMockRecorder
+}
+
+// MockIRefreshTokenRepositoryMockRecorder is the mock recorder for MockIRefreshTokenRepository.
+type MockIRefreshTokenRepositoryMockRecorder struct {
+	mock *MockIRefreshTokenRepository
+}
+
+// NewMockIRefreshTokenRepository creates a new mock instance.
+func NewMockIRefreshTokenRepository(ctrl *gomock.Controller) *MockIRefreshTokenRepository {
+	mock := &MockIRefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockIRefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRefreshTokenRepository) EXPECT() *MockIRefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockIRefreshTokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) CreateRefreshToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).CreateRefreshToken), token)
+}
+
+// FindRefreshTokenByHash mocks base method.
+func (m *MockIRefreshTokenRepository) FindRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRefreshTokenByHash", hash)
+	ret0, _ := ret[0].(*model.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRefreshTokenByHash indicates an expected call of FindRefreshTokenByHash.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) FindRefreshTokenByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshTokenByHash", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).FindRefreshTokenByHash), hash)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockIRefreshTokenRepository) RevokeRefreshTokenFamily(familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) RevokeRefreshTokenFamily(familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).RevokeRefreshTokenFamily), familyID)
+}
+
+// RevokeUserRefreshTokens mocks base method.
+func (m *MockIRefreshTokenRepository) RevokeUserRefreshTokens(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRefreshTokens", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRefreshTokens indicates an expected call of RevokeUserRefreshTokens.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) RevokeUserRefreshTokens(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRefreshTokens", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).RevokeUserRefreshTokens), userID)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockIRefreshTokenRepository) RotateRefreshToken(currentID uint, next *model.RefreshToken) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", currentID, next)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) RotateRefreshToken(currentID, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).RotateRefreshToken), currentID, next)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/revocation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRevocationRepository is a mock of IRevocationRepository interface.
+type MockIRevocationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRevocationRepositoryMockRecorder
+}
+
+// MockIRevocationRepositoryMockRecorder is the mock recorder for MockIRevocationRepository.
+type MockIRevocationRepositoryMockRecorder struct {
+	mock *MockIRevocationRepository
+}
+
+// NewMockIRevocationRepository creates a new mock instance.
+func NewMockIRevocationRepository(ctrl *gomock.Controller) *MockIRevocationRepository {
+	mock := &MockIRevocationRepository{ctrl: ctrl}
+	mock.recorder = &MockIRevocationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRevocationRepository) EXPECT() *MockIRevocationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockIRevocationRepository) IsTokenRevoked(jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockIRevocationRepositoryMockRecorder) IsTokenRevoked(jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockIRevocationRepository)(nil).IsTokenRevoked), jti)
+}
+
+// PurgeExpiredRevocations mocks base method.
+func (m *MockIRevocationRepository) PurgeExpiredRevocations(now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredRevocations", now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredRevocations indicates an expected call of PurgeExpiredRevocations.
+func (mr *MockIRevocationRepositoryMockRecorder) PurgeExpiredRevocations(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredRevocations", reflect.TypeOf((*MockIRevocationRepository)(nil).PurgeExpiredRevocations), now)
+}
+
+// RevokeToken mocks base method.
+func (m *MockIRevocationRepository) RevokeToken(jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockIRevocationRepositoryMockRecorder) RevokeToken(jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockIRevocationRepository)(nil).RevokeToken), jti, expiresAt)
+}
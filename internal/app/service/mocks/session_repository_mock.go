@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/repository/session.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "golangHexagonal/internal/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockISessionRepository is a mock of ISessionRepository interface.
+type MockISessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionRepositoryMockRecorder
+}
+
+// MockISessionRepositoryMockRecorder is the mock recorder for MockISessionRepository.
+type MockISessionRepositoryMockRecorder struct {
+	mock *MockISessionRepository
+}
+
+// NewMockISessionRepository creates a new mock instance.
+func NewMockISessionRepository(ctrl *gomock.Controller) *MockISessionRepository {
+	mock := &MockISessionRepository{ctrl: ctrl}
+	mock.recorder = &MockISessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionRepository) EXPECT() *MockISessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *MockISessionRepository) CreateSession(session *model.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockISessionRepositoryMockRecorder) CreateSession(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockISessionRepository)(nil).CreateSession), session)
+}
+
+// FindSession mocks base method.
+func (m *MockISessionRepository) FindSession(id string) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSession", id)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSession indicates an expected call of FindSession.
+func (mr *MockISessionRepositoryMockRecorder) FindSession(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSession", reflect.TypeOf((*MockISessionRepository)(nil).FindSession), id)
+}
+
+// FindUserSessions mocks base method.
+func (m *MockISessionRepository) FindUserSessions(userID uint) ([]*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserSessions", userID)
+	ret0, _ := ret[0].([]*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserSessions indicates an expected call of FindUserSessions.
+func (mr *MockISessionRepositoryMockRecorder) FindUserSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserSessions", reflect.TypeOf((*MockISessionRepository)(nil).FindUserSessions), userID)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockISessionRepository) RevokeOtherSessions(userID uint, keepID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", userID, keepID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockISessionRepositoryMockRecorder) RevokeOtherSessions(userID, keepID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockISessionRepository)(nil).RevokeOtherSessions), userID, keepID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockISessionRepository) RevokeSession(userID uint, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockISessionRepositoryMockRecorder) RevokeSession(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockISessionRepository)(nil).RevokeSession), userID, id)
+}
+
+// TouchSession mocks base method.
+func (m *MockISessionRepository) TouchSession(id string, seenAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", id, seenAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockISessionRepositoryMockRecorder) TouchSession(id, seenAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockISessionRepository)(nil).TouchSession), id, seenAt)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	model "golangHexagonal/internal/app/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +35,36 @@ func (m *MockIRepository) EXPECT() *MockIRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountDeletedUsersByRole mocks base method.
+func (m *MockIRepository) CountDeletedUsersByRole(role string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeletedUsersByRole", role)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeletedUsersByRole indicates an expected call of CountDeletedUsersByRole.
+func (mr *MockIRepositoryMockRecorder) CountDeletedUsersByRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeletedUsersByRole", reflect.TypeOf((*MockIRepository)(nil).CountDeletedUsersByRole), role)
+}
+
+// CountUsersByRole mocks base method.
+func (m *MockIRepository) CountUsersByRole(role string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsersByRole", role)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsersByRole indicates an expected call of CountUsersByRole.
+func (mr *MockIRepositoryMockRecorder) CountUsersByRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsersByRole", reflect.TypeOf((*MockIRepository)(nil).CountUsersByRole), role)
+}
+
 // CreateUser mocks base method.
 func (m *MockIRepository) CreateUser(user *model.User) error {
 	m.ctrl.T.Helper()
@@ -49,17 +80,17 @@ func (mr *MockIRepositoryMockRecorder) CreateUser(user interface{}) *gomock.Call
 }
 
 // DeleteUser mocks base method.
-func (m *MockIRepository) DeleteUser(id uint) error {
+func (m *MockIRepository) DeleteUser(id, version uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", id)
+	ret := m.ctrl.Call(m, "DeleteUser", id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockIRepositoryMockRecorder) DeleteUser(id interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) DeleteUser(id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIRepository)(nil).DeleteUser), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIRepository)(nil).DeleteUser), id, version)
 }
 
 // FindUserByEmail mocks base method.
@@ -92,19 +123,120 @@ func (mr *MockIRepositoryMockRecorder) FindUserByID(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByID", reflect.TypeOf((*MockIRepository)(nil).FindUserByID), id)
 }
 
-// FindUsers mocks base method.
-func (m *MockIRepository) FindUsers() ([]*model.User, error) {
+// MarkEmailVerified mocks base method.
+func (m *MockIRepository) MarkEmailVerified(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockIRepositoryMockRecorder) MarkEmailVerified(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockIRepository)(nil).MarkEmailVerified), id)
+}
+
+// PurgeDeletedUsers mocks base method.
+func (m *MockIRepository) PurgeDeletedUsers(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockIRepositoryMockRecorder) PurgeDeletedUsers(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockIRepository)(nil).PurgeDeletedUsers), before)
+}
+
+// QueryUsers mocks base method.
+func (m *MockIRepository) QueryUsers(query model.UserQuery) (*model.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryUsers", query)
+	ret0, _ := ret[0].(*model.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryUsers indicates an expected call of QueryUsers.
+func (mr *MockIRepositoryMockRecorder) QueryUsers(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUsers", reflect.TypeOf((*MockIRepository)(nil).QueryUsers), query)
+}
+
+// RecordMFAStep mocks base method.
+func (m *MockIRepository) RecordMFAStep(id uint, step uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFAStep", id, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMFAStep indicates an expected call of RecordMFAStep.
+func (mr *MockIRepositoryMockRecorder) RecordMFAStep(id, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFAStep", reflect.TypeOf((*MockIRepository)(nil).RecordMFAStep), id, step)
+}
+
+// RestoreUser mocks base method.
+func (m *MockIRepository) RestoreUser(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockIRepositoryMockRecorder) RestoreUser(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockIRepository)(nil).RestoreUser), id)
+}
+
+// SeekUsers mocks base method.
+func (m *MockIRepository) SeekUsers(query model.UserQuery, after *model.UserCursor) ([]*model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindUsers")
+	ret := m.ctrl.Call(m, "SeekUsers", query, after)
 	ret0, _ := ret[0].([]*model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindUsers indicates an expected call of FindUsers.
-func (mr *MockIRepositoryMockRecorder) FindUsers() *gomock.Call {
+// SeekUsers indicates an expected call of SeekUsers.
+func (mr *MockIRepositoryMockRecorder) SeekUsers(query, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekUsers", reflect.TypeOf((*MockIRepository)(nil).SeekUsers), query, after)
+}
+
+// UpdateMFA mocks base method.
+func (m *MockIRepository) UpdateMFA(id uint, secret string, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMFA", id, secret, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMFA indicates an expected call of UpdateMFA.
+func (mr *MockIRepositoryMockRecorder) UpdateMFA(id, secret, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMFA", reflect.TypeOf((*MockIRepository)(nil).UpdateMFA), id, secret, enabled)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockIRepository) UpdatePassword(id uint, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockIRepositoryMockRecorder) UpdatePassword(id, hash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsers", reflect.TypeOf((*MockIRepository)(nil).FindUsers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockIRepository)(nil).UpdatePassword), id, hash)
 }
 
 // UpdateUser mocks base method.
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+)
+
+// SessionRevoker ends every session of a user but one.
+type SessionRevoker interface {
+	RevokeOtherSessions(userID uint, currentID string) error
+}
+
+type PasswordChangeService struct {
+	users     repository.IRepository
+	hasher    PasswordHasher
+	passwords PasswordValidator
+	sessions  SessionRevoker
+}
+
+func NewPasswordChangeService(users repository.IRepository, hasher PasswordHasher, passwords PasswordValidator, sessions SessionRevoker) *PasswordChangeService {
+	return &PasswordChangeService{users: users, hasher: hasher, passwords: passwords, sessions: sessions}
+}
+
+// ChangePassword replaces the password of the user after checking the
+// current one, then logs them out of every session except currentID. A wrong
+// current password is reported as a violation of the current_password field.
+func (s *PasswordChangeService) ChangePassword(userID uint, currentID string, input model.ChangePasswordInput) error {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.hasher.Verify(user.Password, input.CurrentPassword)
+	if err != nil || !ok {
+		return &ValidationError{Violations: []FieldViolation{{
+			Field:   "current_password",
+			Code:    "incorrect",
+			Message: "is incorrect",
+		}}}
+	}
+
+	if err := s.passwords.Validate("new_password", input.NewPassword, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.users.UpdatePassword(userID, hashedPassword); err != nil {
+		return err
+	}
+
+	return s.sessions.RevokeOtherSessions(userID, currentID)
+}
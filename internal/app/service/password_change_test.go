@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+	"golangHexagonal/internal/config"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingRevoker is a SessionRevoker that remembers the session it kept.
+type recordingRevoker struct {
+	kept []string
+}
+
+func (r *recordingRevoker) RevokeOtherSessions(userID uint, currentID string) error {
+	r.kept = append(r.kept, currentID)
+	return nil
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := func() *model.User {
+		return &model.User{ID: 1, Name: "test", Email: "test@gmail.com", Password: "hashed:old password"}
+	}
+
+	t.Run("should hash the new password and revoke the other sessions", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(user(), nil)
+		mockRepo.EXPECT().UpdatePassword(uint(1), "hashed:new password").Return(nil)
+		sessions := &recordingRevoker{}
+
+		srv := NewPasswordChangeService(mockRepo, plainHasher{}, anyPassword{}, sessions)
+		err := srv.ChangePassword(1, "current", model.ChangePasswordInput{CurrentPassword: "old password", NewPassword: "new password"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, []string{"current"}, sessions.kept)
+	})
+
+	t.Run("should reject a wrong current password", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(user(), nil)
+
+		srv := NewPasswordChangeService(mockRepo, plainHasher{}, anyPassword{}, &recordingRevoker{})
+		err := srv.ChangePassword(1, "current", model.ChangePasswordInput{CurrentPassword: "guess", NewPassword: "new password"})
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "current_password", validationErr.Violations[0].Field)
+	})
+
+	t.Run("should enforce the password policy", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(user(), nil)
+
+		srv := NewPasswordChangeService(mockRepo, plainHasher{}, NewPasswordPolicy(config.PasswordConfig{}, nil), &recordingRevoker{})
+		err := srv.ChangePassword(1, "current", model.ChangePasswordInput{CurrentPassword: "old password", NewPassword: "short"})
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "new_password", validationErr.Violations[0].Field)
+	})
+}
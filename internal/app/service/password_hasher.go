@@ -0,0 +1,11 @@
+package service
+
+// PasswordHasher hashes and checks passwords. Adapters live in
+// infrastructure/hashing.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash reports whether hash was made with an algorithm or
+	// parameters that are no longer current.
+	NeedsRehash(hash string) bool
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/config"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	// defaultPasswordMaxLength is the number of bytes bcrypt looks at; it
+	// ignores everything after them.
+	defaultPasswordMaxLength = 72
+	// minPersonalInfoLength keeps short names such as "Al" from rejecting
+	// most passwords.
+	minPersonalInfoLength = 3
+)
+
+// PasswordBlocklist knows passwords that appeared in breaches. Adapters live
+// in infrastructure/blocklist.
+type PasswordBlocklist interface {
+	Contains(password string) bool
+}
+
+// PasswordValidator checks a new password of user. Violations name field,
+// the request field the password came in.
+type PasswordValidator interface {
+	Validate(field, password string, user *model.User) error
+}
+
+// PasswordPolicy is the configurable PasswordValidator. Lengths are counted
+// in characters, except the maximum, which is counted in bytes because that
+// is what the hashers limit.
+type PasswordPolicy struct {
+	minLength  int
+	maxLength  int
+	minClasses int
+	blocklist  PasswordBlocklist
+}
+
+// NewPasswordPolicy builds the policy from cfg. blocklist may be nil to skip
+// the breach check.
+func NewPasswordPolicy(cfg config.PasswordConfig, blocklist PasswordBlocklist) *PasswordPolicy {
+	return &PasswordPolicy{
+		minLength:  intOr(cfg.MinLength, defaultPasswordMinLength),
+		maxLength:  intOr(cfg.MaxLength, defaultPasswordMaxLength),
+		minClasses: cfg.MinCharacterClasses,
+		blocklist:  blocklist,
+	}
+}
+
+// Validate returns a *ValidationError listing every rule password breaks,
+// each as a violation of field.
+func (p *PasswordPolicy) Validate(field, password string, user *model.User) error {
+	var violations []FieldViolation
+	add := func(code, message string) {
+		violations = append(violations, FieldViolation{Field: field, Code: code, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		add("too_short", fmt.Sprintf("must be at least %d characters long", p.minLength))
+	}
+
+	if len(password) > p.maxLength {
+		add("too_long", fmt.Sprintf("must be at most %d bytes long", p.maxLength))
+	}
+
+	if p.minClasses > 0 && characterClasses(password) < p.minClasses {
+		add("character_classes", fmt.Sprintf("must mix at least %d of lower case, upper case, digits and symbols", p.minClasses))
+	}
+
+	if containsPersonalInfo(password, user) {
+		add("personal_info", "must not contain your name or email address")
+	}
+
+	if p.blocklist != nil && p.blocklist.Contains(password) {
+		add("breached", "appears in a list of breached passwords")
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
+
+// containsPersonalInfo reports whether password contains the email address,
+// its local part or a word of the name, ignoring case.
+func containsPersonalInfo(password string, user *model.User) bool {
+	if user == nil {
+		return false
+	}
+
+	candidates := strings.Fields(user.Name)
+	if email := strings.TrimSpace(user.Email); email != "" {
+		candidates = append(candidates, email)
+		if local, _, found := strings.Cut(email, "@"); found {
+			candidates = append(candidates, local)
+		}
+	}
+
+	password = strings.ToLower(password)
+	for _, candidate := range candidates {
+		if utf8.RuneCountInString(candidate) < minPersonalInfoLength {
+			continue
+		}
+		if strings.Contains(password, strings.ToLower(candidate)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// breachedList is a PasswordBlocklist backed by a map.
+type breachedList map[string]bool
+
+func (l breachedList) Contains(password string) bool { return l[password] }
+
+func TestService_PasswordPolicy(t *testing.T) {
+	user := &model.User{Name: "Jane Doe", Email: "jane.doe@example.com"}
+	policy := NewPasswordPolicy(config.PasswordConfig{MinCharacterClasses: 3}, breachedList{"Summer2024!": true})
+
+	codes := func(err error) []string {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil
+		}
+		var codes []string
+		for _, violation := range validationErr.Violations {
+			assert.Equal(t, "password", violation.Field)
+			codes = append(codes, violation.Code)
+		}
+		return codes
+	}
+
+	t.Run("should accept a strong password", func(t *testing.T) {
+		assert.Nil(t, policy.Validate("password", "Correct horse 9 battery", user))
+	})
+
+	t.Run("should report every broken rule", func(t *testing.T) {
+		assert.Equal(t, []string{"too_short", "character_classes"}, codes(policy.Validate("password", "abc", user)))
+	})
+
+	t.Run("should reject an empty password", func(t *testing.T) {
+		assert.Contains(t, codes(policy.Validate("password", "", user)), "too_short")
+	})
+
+	t.Run("should limit the length in bytes", func(t *testing.T) {
+		assert.Nil(t, policy.Validate("password", "Aa1"+strings.Repeat("x", 69), user))
+		assert.Equal(t, []string{"too_long"}, codes(policy.Validate("password", "Aa1"+strings.Repeat("é", 35), user)))
+	})
+
+	t.Run("should reject passwords containing the name or email", func(t *testing.T) {
+		assert.Equal(t, []string{"personal_info"}, codes(policy.Validate("password", "MyNameIsJANE-42", user)))
+		assert.Equal(t, []string{"personal_info"}, codes(policy.Validate("password", "Jane.Doe#2024", user)))
+	})
+
+	t.Run("should reject breached passwords", func(t *testing.T) {
+		assert.Equal(t, []string{"breached"}, codes(policy.Validate("password", "Summer2024!", user)))
+	})
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+)
+
+const (
+	defaultPasswordResetTTL      = 30 * time.Minute
+	defaultPasswordResetInterval = 1 * time.Minute
+)
+
+var ErrInvalidResetToken = model.NewError(model.ErrValidation, "invalid or expired reset token")
+
+type PasswordResetService struct {
+	users         repository.IRepository
+	tokens        repository.IOneTimeTokenRepository
+	refreshTokens repository.IRefreshTokenRepository
+	sessions      SessionRevoker
+	mailer        Mailer
+	hasher        PasswordHasher
+	passwords     PasswordValidator
+	resetURL      string
+	ttl           time.Duration
+	interval      time.Duration
+}
+
+// NewPasswordResetService builds the reset flow. resetURL is the link sent to
+// users; the token is appended to it, so it usually ends in "?token=".
+// A reset ends every session of the user through sessions. interval is the
+// minimum time between two reset emails to the same account.
+func NewPasswordResetService(users repository.IRepository, tokens repository.IOneTimeTokenRepository, refreshTokens repository.IRefreshTokenRepository, sessions SessionRevoker, mailer Mailer, hasher PasswordHasher, passwords PasswordValidator, resetURL string, ttl, interval time.Duration) *PasswordResetService {
+	if ttl <= 0 {
+		ttl = defaultPasswordResetTTL
+	}
+	if interval <= 0 {
+		interval = defaultPasswordResetInterval
+	}
+	return &PasswordResetService{
+		users:         users,
+		tokens:        tokens,
+		refreshTokens: refreshTokens,
+		sessions:      sessions,
+		mailer:        mailer,
+		hasher:        hasher,
+		passwords:     passwords,
+		resetURL:      resetURL,
+		ttl:           ttl,
+		interval:      interval,
+	}
+}
+
+// RequestPasswordReset mails a reset link to email. Unknown addresses are
+// ignored silently so that the endpoint cannot be used to discover accounts,
+// and so are accounts mailed less than the interval ago. A new request
+// replaces any reset token issued earlier.
+func (s *PasswordResetService) RequestPasswordReset(email string) error {
+	user, err := s.users.FindUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	latest, err := s.tokens.FindLatestOneTimeToken(user.ID, model.TokenPurposePasswordReset)
+	if err == nil && time.Since(latest.CreatedAt) < s.interval {
+		return nil
+	}
+
+	if err := s.tokens.DeleteUserOneTimeTokens(user.ID, model.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = s.tokens.CreateOneTimeToken(&model.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   model.TokenPurposePasswordReset,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s%s\n\nIf you did not ask for this, you can ignore this email.", user.Name, s.ttl, s.resetURL, token)
+	return s.mailer.Send(user.Email, "Reset your password", body)
+}
+
+// ResetPassword redeems a reset token and sets a new password. Every other
+// reset token of the user, all of their refresh tokens and all of their
+// sessions are invalidated, so stolen access tokens stop working too.
+// A password the policy rejects leaves the token usable for another try.
+func (s *PasswordResetService) ResetPassword(token, password string) error {
+	resetToken, err := s.tokens.FindOneTimeToken(model.TokenPurposePasswordReset, hashToken(token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.users.FindUserByID(resetToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwords.Validate("password", password, user); err != nil {
+		return err
+	}
+
+	consumed, err := s.tokens.ConsumeOneTimeToken(resetToken.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.users.UpdatePassword(resetToken.UserID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.tokens.DeleteUserOneTimeTokens(resetToken.UserID, model.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	if err := s.refreshTokens.RevokeUserRefreshTokens(resetToken.UserID); err != nil {
+		return err
+	}
+
+	// An empty current session keeps none of them.
+	return s.sessions.RevokeOtherSessions(resetToken.UserID, "")
+}
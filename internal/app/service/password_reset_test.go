@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+	"golangHexagonal/internal/config"
+	"golangHexagonal/internal/infrastructure/mail"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RequestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should mail a link with a token whose hash is stored", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockUserRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{ID: 1, Email: "test@gmail.com"}, nil)
+		mockTokenRepo.EXPECT().FindLatestOneTimeToken(uint(1), model.TokenPurposePasswordReset).Return(nil, errors.New("record not found"))
+		mockTokenRepo.EXPECT().DeleteUserOneTimeTokens(uint(1), model.TokenPurposePasswordReset).Return(nil)
+
+		var stored *model.OneTimeToken
+		mockTokenRepo.EXPECT().CreateOneTimeToken(gomock.Any()).DoAndReturn(func(token *model.OneTimeToken) error {
+			stored = token
+			return nil
+		})
+
+		srv := NewPasswordResetService(mockUserRepo, mockTokenRepo, nil, &recordingRevoker{}, mailer, plainHasher{}, anyPassword{}, "https://app/reset?token=", time.Hour, time.Minute)
+		err := srv.RequestPasswordReset("test@gmail.com")
+		assert.Nil(t, err)
+
+		messages := mailer.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "test@gmail.com", messages[0].To)
+
+		token := tokenFromLink(t, messages[0].Body, "https://app/reset?token=")
+		assert.Equal(t, hashToken(token), stored.TokenHash)
+		assert.Equal(t, model.TokenPurposePasswordReset, stored.Purpose)
+	})
+
+	t.Run("should skip an account that was mailed recently", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockUserRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{ID: 1, Email: "test@gmail.com"}, nil)
+		mockTokenRepo.EXPECT().FindLatestOneTimeToken(uint(1), model.TokenPurposePasswordReset).Return(&model.OneTimeToken{CreatedAt: time.Now()}, nil)
+
+		srv := NewPasswordResetService(mockUserRepo, mockTokenRepo, nil, &recordingRevoker{}, mailer, plainHasher{}, anyPassword{}, "https://app/reset?token=", time.Hour, time.Minute)
+		err := srv.RequestPasswordReset("test@gmail.com")
+		assert.Nil(t, err)
+
+		assert.Empty(t, mailer.Messages())
+	})
+
+	t.Run("should do nothing for an unknown email", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mailer := mail.NewMemoryMailer()
+
+		mockUserRepo.EXPECT().FindUserByEmail("unknown@gmail.com").Return(nil, errors.New("record not found"))
+
+		srv := NewPasswordResetService(mockUserRepo, nil, nil, &recordingRevoker{}, mailer, plainHasher{}, anyPassword{}, "https://app/reset?token=", time.Hour, time.Minute)
+		err := srv.RequestPasswordReset("unknown@gmail.com")
+		assert.Nil(t, err)
+
+		assert.Empty(t, mailer.Messages())
+	})
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resetToken := func() *model.OneTimeToken {
+		return &model.OneTimeToken{
+			ID:        3,
+			UserID:    1,
+			Purpose:   model.TokenPurposePasswordReset,
+			TokenHash: hashToken("token"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("should set the password and invalidate outstanding tokens", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mockRefreshRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposePasswordReset, hashToken("token")).Return(resetToken(), nil)
+		mockUserRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1}, nil)
+		mockTokenRepo.EXPECT().ConsumeOneTimeToken(uint(3)).Return(true, nil)
+		mockUserRepo.EXPECT().UpdatePassword(uint(1), gomock.Any()).DoAndReturn(func(_ uint, hash string) error {
+			assert.Equal(t, "hashed:new password", hash)
+			return nil
+		})
+		mockTokenRepo.EXPECT().DeleteUserOneTimeTokens(uint(1), model.TokenPurposePasswordReset).Return(nil)
+		mockRefreshRepo.EXPECT().RevokeUserRefreshTokens(uint(1)).Return(nil)
+
+		sessions := &recordingRevoker{}
+
+		srv := NewPasswordResetService(mockUserRepo, mockTokenRepo, mockRefreshRepo, sessions, nil, plainHasher{}, anyPassword{}, "", time.Hour, time.Minute)
+		err := srv.ResetPassword("token", "new password")
+		assert.Nil(t, err)
+
+		assert.Equal(t, []string{""}, sessions.kept, "every session must be revoked")
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		token := resetToken()
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposePasswordReset, hashToken("token")).Return(token, nil)
+
+		srv := NewPasswordResetService(nil, mockTokenRepo, nil, &recordingRevoker{}, nil, plainHasher{}, anyPassword{}, "", time.Hour, time.Minute)
+		err := srv.ResetPassword("token", "new password")
+		assert.Equal(t, ErrInvalidResetToken, err)
+	})
+
+	t.Run("should keep the token when the password breaks the policy", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposePasswordReset, hashToken("token")).Return(resetToken(), nil)
+		mockUserRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1}, nil)
+
+		srv := NewPasswordResetService(mockUserRepo, mockTokenRepo, nil, &recordingRevoker{}, nil, plainHasher{}, NewPasswordPolicy(config.PasswordConfig{}, nil), "", time.Hour, time.Minute)
+		err := srv.ResetPassword("token", "short")
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("should reject a token that was already used", func(t *testing.T) {
+		mockUserRepo := mocks.NewMockIRepository(ctrl)
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposePasswordReset, hashToken("token")).Return(resetToken(), nil)
+		mockUserRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1}, nil)
+		mockTokenRepo.EXPECT().ConsumeOneTimeToken(uint(3)).Return(false, nil)
+
+		srv := NewPasswordResetService(mockUserRepo, mockTokenRepo, nil, &recordingRevoker{}, nil, plainHasher{}, anyPassword{}, "", time.Hour, time.Minute)
+		err := srv.ResetPassword("token", "new password")
+		assert.Equal(t, ErrInvalidResetToken, err)
+	})
+
+	t.Run("should reject an unknown token", func(t *testing.T) {
+		mockTokenRepo := mocks.NewMockIOneTimeTokenRepository(ctrl)
+		mockTokenRepo.EXPECT().FindOneTimeToken(model.TokenPurposePasswordReset, hashToken("unknown")).Return(nil, errors.New("record not found"))
+
+		srv := NewPasswordResetService(nil, mockTokenRepo, nil, &recordingRevoker{}, nil, plainHasher{}, anyPassword{}, "", time.Hour, time.Minute)
+		err := srv.ResetPassword("unknown", "new password")
+		assert.Equal(t, ErrInvalidResetToken, err)
+	})
+}
+
+// tokenFromLink extracts the token that follows prefix in a mail body.
+func tokenFromLink(t *testing.T, body, prefix string) string {
+	_, rest, found := strings.Cut(body, prefix)
+	assert.True(t, found)
+
+	token, _, _ := strings.Cut(rest, "\n")
+	return token
+}
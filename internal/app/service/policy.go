@@ -0,0 +1,45 @@
+package service
+
+import "golangHexagonal/internal/app/model"
+
+var ErrForbidden = model.NewError(model.ErrForbidden, "forbidden")
+
+// Actor is the authenticated caller on whose behalf a service method runs.
+type Actor struct {
+	UserID uint
+	Role   string
+}
+
+type Action string
+
+const (
+	ActionUpdateUser  Action = "user:update"
+	ActionDeleteUser  Action = "user:delete"
+	ActionRestoreUser Action = "user:restore"
+)
+
+// AccessPolicy decides whether actor may perform action on the user account
+// identified by targetID. It returns ErrForbidden when it may not.
+type AccessPolicy interface {
+	Authorize(actor Actor, action Action, targetID uint) error
+}
+
+// OwnershipPolicy lets admins act on any account and everybody else only on
+// their own.
+type OwnershipPolicy struct{}
+
+func NewOwnershipPolicy() *OwnershipPolicy {
+	return &OwnershipPolicy{}
+}
+
+func (*OwnershipPolicy) Authorize(actor Actor, _ Action, targetID uint) error {
+	if actor.Role == model.RoleAdmin {
+		return nil
+	}
+
+	if actor.UserID == 0 || actor.UserID != targetID {
+		return ErrForbidden
+	}
+
+	return nil
+}
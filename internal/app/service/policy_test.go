@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"golangHexagonal/internal/app/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_OwnershipPolicy(t *testing.T) {
+	policy := NewOwnershipPolicy()
+
+	t.Run("should allow users to act on their own account", func(t *testing.T) {
+		err := policy.Authorize(Actor{UserID: 1, Role: model.RoleUser}, ActionUpdateUser, 1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should forbid users to act on another account", func(t *testing.T) {
+		err := policy.Authorize(Actor{UserID: 1, Role: model.RoleUser}, ActionDeleteUser, 2)
+		assert.Equal(t, ErrForbidden, err)
+	})
+
+	t.Run("should allow admins to act on any account", func(t *testing.T) {
+		err := policy.Authorize(Actor{UserID: 1, Role: model.RoleAdmin}, ActionDeleteUser, 2)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should forbid anonymous actors", func(t *testing.T) {
+		err := policy.Authorize(Actor{}, ActionUpdateUser, 0)
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StartPurger calls purge every interval in a background goroutine until ctx
+// is cancelled. Failures are logged and retried on the next tick.
+func StartPurger(ctx context.Context, name string, interval time.Duration, purge func(now time.Time) (int64, error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				purged, err := purge(now)
+				if err != nil {
+					log.Printf("purge %s: %v", name, err)
+					continue
+				}
+				if purged > 0 {
+					log.Printf("purge %s: removed %d", name, purged)
+				}
+			}
+		}
+	}()
+}
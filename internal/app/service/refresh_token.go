@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+)
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidRefreshToken = model.NewError(model.ErrUnauthorized, "invalid refresh token")
+	ErrRefreshTokenReused  = model.NewError(model.ErrUnauthorized, "refresh token reuse detected")
+)
+
+type RefreshTokenService struct {
+	repo     repository.IRefreshTokenRepository
+	sessions repository.ISessionRepository
+}
+
+// NewRefreshTokenService builds the refresh token flow. A reused token also
+// ends its session in sessions, which stops the access tokens of the session.
+func NewRefreshTokenService(repo repository.IRefreshTokenRepository, sessions repository.ISessionRepository) *RefreshTokenService {
+	return &RefreshTokenService{repo: repo, sessions: sessions}
+}
+
+// IssueRefreshToken starts the token family of a new session and returns
+// its first refresh token. The session ID is used as the family ID.
+func (s *RefreshTokenService) IssueRefreshToken(userID uint, sessionID string) (string, error) {
+	token, record, err := newRefreshToken(userID, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.CreateRefreshToken(record); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one in the same
+// family and returns the record of the new token with it. Presenting a token
+// that was already rotated revokes the whole family, since either the client
+// or an attacker holds a stolen copy.
+func (s *RefreshTokenService) RotateRefreshToken(token string) (*model.RefreshToken, string, error) {
+	current, err := s.repo.FindRefreshTokenByHash(hashToken(token))
+	if err != nil {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	if current.RotatedAt != nil {
+		return nil, "", s.revokeFamily(current)
+	}
+
+	if current.RevokedAt != nil || time.Now().After(current.ExpiresAt) {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	next, record, err := newRefreshToken(current.UserID, current.FamilyID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rotated, err := s.repo.RotateRefreshToken(current.ID, record)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !rotated {
+		return nil, "", s.revokeFamily(current)
+	}
+
+	return record, next, nil
+}
+
+// revokeFamily revokes every refresh token in the family of reused and the
+// session the family belongs to.
+func (s *RefreshTokenService) revokeFamily(reused *model.RefreshToken) error {
+	if err := s.repo.RevokeRefreshTokenFamily(reused.FamilyID); err != nil {
+		return err
+	}
+	// The family ID is the session ID. The session may be revoked already.
+	if _, err := s.sessions.RevokeSession(reused.UserID, reused.FamilyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func newRefreshToken(userID uint, familyID string) (string, *model.RefreshToken, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, &model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_IssueRefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should store only the hash of a new token in the session's family", func(t *testing.T) {
+		var stored *model.RefreshToken
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(token *model.RefreshToken) error {
+			stored = token
+			return nil
+		})
+
+		srv := NewRefreshTokenService(mockRepo, nil)
+		token, err := srv.IssueRefreshToken(1, "session")
+		assert.Nil(t, err)
+
+		assert.NotEmpty(t, token)
+		assert.Equal(t, uint(1), stored.UserID)
+		assert.Equal(t, "session", stored.FamilyID)
+		assert.Equal(t, hashToken(token), stored.TokenHash)
+		assert.NotEqual(t, token, stored.TokenHash)
+	})
+
+	t.Run("should return error when create fails", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(errors.New("error"))
+
+		srv := NewRefreshTokenService(mockRepo, nil)
+		token, err := srv.IssueRefreshToken(1, "session")
+		assert.NotNil(t, err)
+
+		assert.Empty(t, token)
+	})
+}
+
+func TestService_RotateRefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := func() *model.RefreshToken {
+		return &model.RefreshToken{
+			ID:        7,
+			UserID:    1,
+			FamilyID:  "family",
+			TokenHash: hashToken("old"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("should rotate a valid token within its family", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().FindRefreshTokenByHash(hashToken("old")).Return(current(), nil)
+		mockRepo.EXPECT().RotateRefreshToken(uint(7), gomock.Any()).DoAndReturn(func(_ uint, next *model.RefreshToken) (bool, error) {
+			assert.Equal(t, "family", next.FamilyID)
+			assert.Equal(t, uint(1), next.UserID)
+			return true, nil
+		})
+
+		srv := NewRefreshTokenService(mockRepo, nil)
+		rotated, token, err := srv.RotateRefreshToken("old")
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint(1), rotated.UserID)
+		assert.Equal(t, "family", rotated.FamilyID)
+		assert.NotEmpty(t, token)
+		assert.NotEqual(t, "old", token)
+	})
+
+	t.Run("should revoke the family and its session when a rotated token is reused", func(t *testing.T) {
+		rotatedAt := time.Now().Add(-time.Minute)
+		token := current()
+		token.RotatedAt = &rotatedAt
+
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().FindRefreshTokenByHash(hashToken("old")).Return(token, nil)
+		mockRepo.EXPECT().RevokeRefreshTokenFamily("family").Return(nil)
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().RevokeSession(uint(1), "family").Return(true, nil)
+
+		srv := NewRefreshTokenService(mockRepo, mockSessionRepo)
+		_, _, err := srv.RotateRefreshToken("old")
+
+		assert.True(t, errors.Is(err, ErrRefreshTokenReused))
+	})
+
+	t.Run("should revoke the family when a concurrent rotation wins", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().FindRefreshTokenByHash(hashToken("old")).Return(current(), nil)
+		mockRepo.EXPECT().RotateRefreshToken(uint(7), gomock.Any()).Return(false, nil)
+		mockRepo.EXPECT().RevokeRefreshTokenFamily("family").Return(nil)
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().RevokeSession(uint(1), "family").Return(true, nil)
+
+		srv := NewRefreshTokenService(mockRepo, mockSessionRepo)
+		_, _, err := srv.RotateRefreshToken("old")
+
+		assert.True(t, errors.Is(err, ErrRefreshTokenReused))
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		token := current()
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().FindRefreshTokenByHash(hashToken("old")).Return(token, nil)
+
+		srv := NewRefreshTokenService(mockRepo, nil)
+		_, _, err := srv.RotateRefreshToken("old")
+
+		assert.True(t, errors.Is(err, ErrInvalidRefreshToken))
+	})
+
+	t.Run("should reject an unknown token", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockRepo.EXPECT().FindRefreshTokenByHash(hashToken("unknown")).Return(nil, errors.New("record not found"))
+
+		srv := NewRefreshTokenService(mockRepo, nil)
+		_, _, err := srv.RotateRefreshToken("unknown")
+
+		assert.True(t, errors.Is(err, ErrInvalidRefreshToken))
+	})
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
+)
+
+// sessionTouchInterval limits how often the last-seen time of a session is
+// written, so that not every request costs an update.
+const sessionTouchInterval = 1 * time.Minute
+
+// maxUserAgentLength matches the size of Session.UserAgent.
+const maxUserAgentLength = 255
+
+var (
+	ErrSessionRevoked  = model.NewError(model.ErrUnauthorized, "session has been revoked")
+	ErrSessionNotFound = model.NewError(model.ErrNotFound, "session not found")
+)
+
+type SessionService struct {
+	sessions      repository.ISessionRepository
+	refreshTokens repository.IRefreshTokenRepository
+	now           func() time.Time
+}
+
+func NewSessionService(sessions repository.ISessionRepository, refreshTokens repository.IRefreshTokenRepository) *SessionService {
+	return &SessionService{sessions: sessions, refreshTokens: refreshTokens, now: time.Now}
+}
+
+// StartSession records a new login of the user from the given client.
+func (s *SessionService) StartSession(userID uint, userAgent, ip string) (*model.Session, error) {
+	id, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := s.now()
+	session := &model.Session{
+		ID:         id,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CheckSession implements handler.SessionChecker. It returns
+// ErrSessionRevoked for unknown and revoked sessions and refreshes the
+// last-seen time of active ones. Other errors are returned as they are, so
+// that a failing database does not log everyone out.
+func (s *SessionService) CheckSession(id string) error {
+	session, err := s.sessions.FindSession(id)
+	if errors.Is(err, model.ErrNotFound) {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	now := s.now()
+	if now.Sub(session.LastSeenAt) < sessionTouchInterval {
+		return nil
+	}
+	return s.sessions.TouchSession(id, now)
+}
+
+// ListSessions returns the active sessions of the user with currentID
+// marked as the caller's.
+func (s *SessionService) ListSessions(userID uint, currentID string) ([]*model.Session, error) {
+	sessions, err := s.sessions.FindUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends a session of the user: its access tokens stop working
+// at the next request and its refresh tokens are revoked.
+func (s *SessionService) RevokeSession(userID uint, id string) error {
+	revoked, err := s.sessions.RevokeSession(userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	return s.refreshTokens.RevokeRefreshTokenFamily(id)
+}
+
+// RevokeOtherSessions ends every session of the user except currentID.
+func (s *SessionService) RevokeOtherSessions(userID uint, currentID string) error {
+	ids, err := s.sessions.RevokeOtherSessions(userID, currentID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.refreshTokens.RevokeRefreshTokenFamily(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_StartSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should record the client of a new login", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+
+		var stored *model.Session
+		mockSessionRepo.EXPECT().CreateSession(gomock.Any()).DoAndReturn(func(session *model.Session) error {
+			stored = session
+			return nil
+		})
+
+		srv := NewSessionService(mockSessionRepo, nil)
+		session, err := srv.StartSession(1, "curl/8.0", "10.0.0.1")
+		assert.Nil(t, err)
+
+		assert.Equal(t, stored, session)
+		assert.NotEmpty(t, session.ID)
+		assert.Equal(t, uint(1), session.UserID)
+		assert.Equal(t, "curl/8.0", session.UserAgent)
+		assert.Equal(t, "10.0.0.1", session.IP)
+	})
+}
+
+func TestService_CheckSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+
+	newService := func(repo *mocks.MockISessionRepository) *SessionService {
+		srv := NewSessionService(repo, nil)
+		srv.now = func() time.Time { return now }
+		return srv
+	}
+
+	t.Run("should accept a recently seen session without writing", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().FindSession("session").Return(&model.Session{ID: "session", LastSeenAt: now.Add(-time.Second)}, nil)
+
+		assert.Nil(t, newService(mockSessionRepo).CheckSession("session"))
+	})
+
+	t.Run("should update the last-seen time of an idle session", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().FindSession("session").Return(&model.Session{ID: "session", LastSeenAt: now.Add(-time.Hour)}, nil)
+		mockSessionRepo.EXPECT().TouchSession("session", now).Return(nil)
+
+		assert.Nil(t, newService(mockSessionRepo).CheckSession("session"))
+	})
+
+	t.Run("should reject revoked and unknown sessions", func(t *testing.T) {
+		revokedAt := now.Add(-time.Minute)
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().FindSession("revoked").Return(&model.Session{ID: "revoked", RevokedAt: &revokedAt}, nil)
+		mockSessionRepo.EXPECT().FindSession("unknown").Return(nil, model.NewError(model.ErrNotFound, "session not found"))
+
+		srv := newService(mockSessionRepo)
+		assert.Equal(t, ErrSessionRevoked, srv.CheckSession("revoked"))
+		assert.Equal(t, ErrSessionRevoked, srv.CheckSession("unknown"))
+	})
+
+	t.Run("should pass other errors through", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().FindSession("session").Return(nil, errors.New("connection refused"))
+
+		err := newService(mockSessionRepo).CheckSession("session")
+		assert.NotNil(t, err)
+		assert.NotEqual(t, ErrSessionRevoked, err)
+	})
+}
+
+func TestService_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should revoke the refresh tokens of the session", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockRefreshRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockSessionRepo.EXPECT().RevokeSession(uint(1), "session").Return(true, nil)
+		mockRefreshRepo.EXPECT().RevokeRefreshTokenFamily("session").Return(nil)
+
+		srv := NewSessionService(mockSessionRepo, mockRefreshRepo)
+		assert.Nil(t, srv.RevokeSession(1, "session"))
+	})
+
+	t.Run("should return not found for a session of another user", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().RevokeSession(uint(1), "other").Return(false, nil)
+
+		srv := NewSessionService(mockSessionRepo, nil)
+		assert.Equal(t, ErrSessionNotFound, srv.RevokeSession(1, "other"))
+	})
+
+	t.Run("should keep the current session when revoking the others", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockRefreshRepo := mocks.NewMockIRefreshTokenRepository(ctrl)
+		mockSessionRepo.EXPECT().RevokeOtherSessions(uint(1), "current").Return([]string{"laptop", "phone"}, nil)
+		mockRefreshRepo.EXPECT().RevokeRefreshTokenFamily("laptop").Return(nil)
+		mockRefreshRepo.EXPECT().RevokeRefreshTokenFamily("phone").Return(nil)
+
+		srv := NewSessionService(mockSessionRepo, mockRefreshRepo)
+		assert.Nil(t, srv.RevokeOtherSessions(1, "current"))
+	})
+}
+
+func TestService_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should mark the current session", func(t *testing.T) {
+		mockSessionRepo := mocks.NewMockISessionRepository(ctrl)
+		mockSessionRepo.EXPECT().FindUserSessions(uint(1)).Return([]*model.Session{{ID: "laptop"}, {ID: "current"}}, nil)
+
+		srv := NewSessionService(mockSessionRepo, nil)
+		sessions, err := srv.ListSessions(1, "current")
+		assert.Nil(t, err)
+
+		assert.False(t, sessions[0].Current)
+		assert.True(t, sessions[1].Current)
+	})
+}
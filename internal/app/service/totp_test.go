@@ -0,0 +1,75 @@
+package service
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret is the SHA-1 test key of RFC 6238, "12345678901234567890",
+// in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP(t *testing.T) {
+	t.Run("should match the RFC 6238 test vectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:         "287082",
+			1111111109: "081804",
+			1234567890: "005924",
+			2000000000: "279037",
+		}
+
+		for unix, expected := range vectors {
+			code, err := totpCode(rfc6238Secret, uint64(unix/totpPeriod))
+			assert.Nil(t, err)
+			assert.Equal(t, expected, code, "time %d", unix)
+		}
+	})
+
+	t.Run("should accept a code from the adjacent step", func(t *testing.T) {
+		now := time.Unix(1111111109, 0)
+		previous, _ := totpCode(rfc6238Secret, uint64(now.Unix()/totpPeriod-1))
+
+		step, ok := validateTOTP(rfc6238Secret, previous, now, 0)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(now.Unix()/totpPeriod-1), step)
+
+		_, ok = validateTOTP(rfc6238Secret, previous, now.Add(2*totpPeriod*time.Second), 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject a code of an already used step", func(t *testing.T) {
+		now := time.Unix(59, 0)
+
+		_, ok := validateTOTP(rfc6238Secret, "287082", now, 1)
+		assert.False(t, ok)
+		_, ok = validateTOTP(rfc6238Secret, "287082", now, 0)
+		assert.True(t, ok)
+	})
+
+	t.Run("should reject malformed codes", func(t *testing.T) {
+		now := time.Unix(59, 0)
+
+		for _, tc := range []struct{ secret, code string }{
+			{rfc6238Secret, ""},
+			{rfc6238Secret, "28708"},
+			{"not base32!", "287082"},
+		} {
+			_, ok := validateTOTP(tc.secret, tc.code, now, 0)
+			assert.False(t, ok, tc.code)
+		}
+	})
+
+	t.Run("should build an otpauth uri", func(t *testing.T) {
+		uri, err := url.Parse(totpURI("golang-hexagonal", "test@gmail.com", rfc6238Secret))
+		assert.Nil(t, err)
+
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, "totp", uri.Host)
+		assert.Equal(t, "/golang-hexagonal:test@gmail.com", uri.Path)
+		assert.Equal(t, rfc6238Secret, uri.Query().Get("secret"))
+		assert.Equal(t, "golang-hexagonal", uri.Query().Get("issuer"))
+	})
+}
@@ -4,55 +4,319 @@ import (
 	"errors"
 	"golangHexagonal/internal/app/model"
 	"golangHexagonal/internal/app/repository"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
 
-	"golang.org/x/crypto/bcrypt"
+var (
+	ErrAdminExists        = model.NewError(model.ErrConflict, "an admin account already exists")
+	ErrAdminDeleted       = model.NewError(model.ErrConflict, "an admin account exists but is deleted; restore it with POST /users/:id/restore")
+	ErrInvalidCredentials = model.NewError(model.ErrUnauthorized, "invalid email or password")
 )
 
 type UserService struct {
-	repo repository.IRepository
+	repo      repository.IRepository
+	policy    AccessPolicy
+	verifier  VerificationSender
+	hasher    PasswordHasher
+	passwords PasswordValidator
+	cursors   *CursorCodec
+	sessions  SessionRevoker
+
+	requireVerifiedEmail bool
+
+	// dummyHash is checked instead of a password hash for unknown emails.
+	dummyHash     string
+	dummyHashOnce sync.Once
 }
 
-func NewUserService(repo repository.IRepository) *UserService {
-	return &UserService{repo: repo}
+// NewUserService builds the user service. New passwords must pass passwords,
+// list cursors are signed by cursors and deleted users are logged out of
+// sessions. When requireVerifiedEmail is set, AuthenticateUser refuses
+// accounts whose email is not verified yet.
+func NewUserService(repo repository.IRepository, policy AccessPolicy, verifier VerificationSender, hasher PasswordHasher, passwords PasswordValidator, cursors *CursorCodec, sessions SessionRevoker, requireVerifiedEmail bool) *UserService {
+	return &UserService{repo: repo, policy: policy, verifier: verifier, hasher: hasher, passwords: passwords, cursors: cursors, sessions: sessions, requireVerifiedEmail: requireVerifiedEmail}
 }
 
 func (s *UserService) GetUserByID(id uint) (*model.User, error) {
 	return s.repo.FindUserByID(id)
 }
 
+// CreateUser registers a regular user and mails them a verification link.
+// Roles and the verified state cannot be chosen at sign-up. A failure to send
+// the link does not undo the sign-up; the user can ask for a new one.
 func (s *UserService) CreateUser(user *model.User) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	user.Role = model.RoleUser
+	user.EmailVerified = false
+	user.MFASecret = ""
+	user.MFAEnabled = false
+	if err := s.createUser(user); err != nil {
+		return err
+	}
+
+	s.sendVerification(user)
+	return nil
+}
+
+// BootstrapAdmin creates the first admin account. It refuses to run once any
+// admin exists, so it cannot be used to escalate privileges later. Deleted
+// admins count until they are purged.
+func (s *UserService) BootstrapAdmin(user *model.User) error {
+	admins, err := s.repo.CountUsersByRole(model.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins > 0 {
+		return ErrAdminExists
+	}
+
+	deleted, err := s.repo.CountDeletedUsersByRole(model.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		return ErrAdminDeleted
+	}
+
+	user.Role = model.RoleAdmin
+	user.EmailVerified = true
+	return s.createUser(user)
+}
+
+func (s *UserService) createUser(user *model.User) error {
+	if err := s.passwords.Validate("password", user.Password, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(user.Password)
 	if err != nil {
 		return err
 	}
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	return s.repo.CreateUser(user)
+}
 
+// UpdateUser replaces the profile of user on behalf of actor, keeping the
+// stored password, role and MFA settings so that callers cannot change their
+// own privileges or drop their second factor through a profile update.
+// Passwords are changed with PasswordChangeService instead. A new email
+// address has to be verified again.
+//
+// user.Version is the version the caller read; the update fails with
+// model.ErrUserModified if the account has changed since.
+func (s *UserService) UpdateUser(actor Actor, user *model.User) error {
+	existing, err := s.findForUpdate(actor, user.ID, user.Version)
+	if err != nil {
+		return err
+	}
+
+	return s.saveProfile(existing, user)
+}
+
+// PatchUser changes the fields set in patch on version of the user on behalf
+// of actor and returns the updated user. It is subject to the same rules as
+// UpdateUser.
+func (s *UserService) PatchUser(actor Actor, id, version uint, patch model.UserPatch) (*model.User, error) {
+	existing, err := s.findForUpdate(actor, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	user := *existing
+	patch.Apply(&user)
+	if err := s.saveProfile(existing, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
-func (s *UserService) UpdateUser(user *model.User) error {
-	return s.repo.UpdateUser(user)
+// findForUpdate loads the user actor wants to update, provided it still has
+// version. The repository checks the version again when writing.
+func (s *UserService) findForUpdate(actor Actor, id, version uint) (*model.User, error) {
+	if err := s.policy.Authorize(actor, ActionUpdateUser, id); err != nil {
+		return nil, err
+	}
 
+	existing, err := s.repo.FindUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Version != version {
+		return nil, model.ErrUserModified
+	}
+	return existing, nil
 }
 
-func (s *UserService) DeleteUser(id uint) error {
-	return s.repo.DeleteUser(id)
+// saveProfile stores the profile fields of user over existing.
+func (s *UserService) saveProfile(existing, user *model.User) error {
+	user.Password = existing.Password
+	user.CreatedAt = existing.CreatedAt
+	user.Role = existing.Role
+	user.MFASecret = existing.MFASecret
+	user.MFAEnabled = existing.MFAEnabled
+	emailChanged := user.Email != existing.Email
+	user.EmailVerified = existing.EmailVerified && !emailChanged
+
+	if err := s.repo.UpdateUser(user); err != nil {
+		return err
+	}
+
+	if emailChanged {
+		s.sendVerification(user)
+	}
+	return nil
 }
 
+func (s *UserService) sendVerification(user *model.User) {
+	if err := s.verifier.SendVerification(user); err != nil {
+		log.Printf("send verification email to user %d: %v", user.ID, err)
+	}
+}
+
+// DeleteUser soft-deletes version of the user and ends all of their
+// sessions. The account can be restored until it is purged.
+func (s *UserService) DeleteUser(actor Actor, id, version uint) error {
+	if err := s.policy.Authorize(actor, ActionDeleteUser, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteUser(id, version); err != nil {
+		return err
+	}
+
+	// An empty current session keeps none of them.
+	return s.sessions.RevokeOtherSessions(id, "")
+}
+
+// RestoreUser brings back a deleted user. Their sessions stay revoked, so
+// they have to log in again.
+func (s *UserService) RestoreUser(actor Actor, id uint) (*model.User, error) {
+	if err := s.policy.Authorize(actor, ActionRestoreUser, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RestoreUser(id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindUserByID(id)
+}
+
+// AuthenticateUser checks the password of the account with email. A
+// password whose stored hash uses an outdated algorithm or parameters is
+// rehashed on the way. Unknown emails take as long as wrong passwords, so the
+// response time does not reveal which emails are registered.
 func (s *UserService) AuthenticateUser(email, password string) (*model.User, error) {
 	user, err := s.repo.FindUserByEmail(email)
+	if errors.Is(err, model.ErrNotFound) {
+		s.verifyDummyHash(password)
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	if err != nil {
-		return nil, errors.New("invalid credentials")
+	ok, err := s.hasher.Verify(user.Password, password)
+	if err != nil || !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehashPassword(user, password)
+	}
+
+	if s.requireVerifiedEmail && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
 	}
 
 	return user, nil
 }
 
-func (s *UserService) GetUsers() ([]*model.User, error) {
-	return s.repo.FindUsers()
+// verifyDummyHash checks password against a hash of a random password made
+// by the current hasher, and ignores the result.
+func (s *UserService) verifyDummyHash(password string) {
+	s.dummyHashOnce.Do(func() {
+		dummy, err := randomToken(16)
+		if err == nil {
+			s.dummyHash, err = s.hasher.Hash(dummy)
+		}
+		if err != nil {
+			log.Printf("hash dummy password: %v", err)
+		}
+	})
+	_, _ = s.hasher.Verify(s.dummyHash, password)
+}
+
+// rehashPassword replaces the stored hash of user. Failures are only logged
+// since the login itself succeeded; the next login tries again.
+func (s *UserService) rehashPassword(user *model.User, password string) {
+	hashedPassword, err := s.hasher.Hash(password)
+	if err == nil {
+		err = s.repo.UpdatePassword(user.ID, hashedPassword)
+	}
+	if err != nil {
+		log.Printf("rehash password of user %d: %v", user.ID, err)
+		return
+	}
+	user.Password = hashedPassword
+}
+
+// GetUsers returns one page of users. Missing paging parameters take their
+// defaults and an unknown sort field is rejected.
+func (s *UserService) GetUsers(query model.UserQuery) (*model.UserPage, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	query.PerPage = usersPerPage(query.PerPage)
+	if query.Sort == "" {
+		query.Sort = "id"
+	}
+	if !slices.Contains(model.UserSortFields, query.Sort) {
+		return nil, &ValidationError{Violations: []FieldViolation{{
+			Field:   "sort",
+			Code:    "oneof",
+			Message: "must be one of " + strings.Join(model.UserSortFields, ", "),
+		}}}
+	}
+
+	return s.repo.QueryUsers(query)
+}
+
+// GetUsersAfter returns the users after cursor in creation order, using the
+// filters of query but not its page or sort. An empty cursor starts at the
+// first user; any other has to come from a listing with the same filters.
+func (s *UserService) GetUsersAfter(query model.UserQuery, cursor string) (*model.UserCursorPage, error) {
+	var after *model.UserCursor
+	if cursor != "" {
+		position, err := s.cursors.Decode(cursor, query)
+		if err != nil {
+			return nil, err
+		}
+		after = &position
+	}
+
+	perPage := usersPerPage(query.PerPage)
+	// One extra user tells whether there is a next page.
+	query.PerPage = perPage + 1
+	users, err := s.repo.SeekUsers(query, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.UserCursorPage{Users: users, PerPage: perPage}
+	if len(users) > perPage {
+		page.Users = users[:perPage]
+		last := page.Users[perPage-1]
+		page.NextCursor = s.cursors.Encode(model.UserCursor{CreatedAt: last.CreatedAt, ID: last.ID}, query)
+	}
+	return page, nil
+}
+
+// usersPerPage applies the default and the cap to a requested page size.
+func usersPerPage(perPage int) int {
+	if perPage < 1 {
+		return model.DefaultUsersPerPage
+	}
+	return min(perPage, model.MaxUsersPerPage)
 }
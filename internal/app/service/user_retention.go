@@ -0,0 +1,32 @@
+package service
+
+import (
+	"time"
+
+	"golangHexagonal/internal/app/repository"
+)
+
+// DefaultDeletedUserRetention is how long deleted users can be restored when
+// no retention is configured.
+const DefaultDeletedUserRetention = 30 * 24 * time.Hour
+
+// UserRetention purges deleted users once they can no longer be restored.
+type UserRetention struct {
+	repo      repository.IRepository
+	retention time.Duration
+}
+
+// NewUserRetention keeps deleted users for retention, or for
+// DefaultDeletedUserRetention when it is zero.
+func NewUserRetention(repo repository.IRepository, retention time.Duration) *UserRetention {
+	if retention <= 0 {
+		retention = DefaultDeletedUserRetention
+	}
+	return &UserRetention{repo: repo, retention: retention}
+}
+
+// PurgeDeletedUsers permanently removes users deleted longer than the
+// retention period ago. It is meant to run with StartPurger.
+func (r *UserRetention) PurgeDeletedUsers(now time.Time) (int64, error) {
+	return r.repo.PurgeDeletedUsers(now.Add(-r.retention))
+}
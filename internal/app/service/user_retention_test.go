@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golangHexagonal/internal/app/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_PurgeDeletedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should purge users deleted before the retention period", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().PurgeDeletedUsers(now.Add(-7*24*time.Hour)).Return(int64(3), nil)
+
+		purged, err := NewUserRetention(mockRepo, 7*24*time.Hour).PurgeDeletedUsers(now)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("should fall back to the default retention", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().PurgeDeletedUsers(now.Add(-DefaultDeletedUserRetention)).Return(int64(0), nil)
+
+		_, err := NewUserRetention(mockRepo, 0).PurgeDeletedUsers(now)
+		assert.Nil(t, err)
+	})
+}
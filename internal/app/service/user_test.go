@@ -3,9 +3,13 @@ package service
 import (
 	"errors"
 	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/repository"
 	"golangHexagonal/internal/app/service/mocks"
+	"golangHexagonal/internal/config"
+	"strings"
 
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +28,7 @@ func TestService_GetUser(t *testing.T) {
 			Password: "123456",
 		}, nil)
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		user, err := srv.GetUserByID(1)
 		assert.Nil(t, err)
 
@@ -35,7 +39,7 @@ func TestService_GetUser(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
 		mockRepo.EXPECT().FindUserByID(uint(1)).Return(nil, errors.New("error"))
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		user, err := srv.GetUserByID(1)
 		assert.NotNil(t, err)
 
@@ -51,7 +55,7 @@ func TestService_CreateUser(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
 		mockRepo.EXPECT().CreateUser(gomock.Any()).Return(nil)
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		err := srv.CreateUser(&model.User{
 			Email:    "test@gmail.com",
 			ID:       1,
@@ -61,11 +65,46 @@ func TestService_CreateUser(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("should send a verification email", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().CreateUser(gomock.Any()).Return(nil)
+		verifier := &recordingVerifier{}
+
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), verifier, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, false)
+		err := srv.CreateUser(&model.User{Email: "test@gmail.com", Password: "123456", EmailVerified: true})
+		assert.Nil(t, err)
+
+		assert.Len(t, verifier.users, 1)
+		assert.False(t, verifier.users[0].EmailVerified)
+	})
+
+	t.Run("should reject a password that breaks the policy", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), noopVerifier{}, plainHasher{}, NewPasswordPolicy(config.PasswordConfig{}, nil), testCursors, &recordingRevoker{}, false)
+		err := srv.CreateUser(&model.User{Email: "test@gmail.com", Password: ""})
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("should always create a regular user", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			assert.Equal(t, model.RoleUser, user.Role)
+			return nil
+		})
+
+		srv := newTestUserService(mockRepo)
+		err := srv.CreateUser(&model.User{Email: "test@gmail.com", Password: "123456", Role: model.RoleAdmin})
+		assert.Nil(t, err)
+	})
+
 	t.Run("should return error when create user", func(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
 		mockRepo.EXPECT().CreateUser(gomock.Any()).Return(errors.New("error"))
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		err := srv.CreateUser(&model.User{
 			Email:    "test@gmail.com",
 			ID:       1,
@@ -76,16 +115,58 @@ func TestService_CreateUser(t *testing.T) {
 	})
 }
 
+func TestService_BootstrapAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("should create the first admin", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().CountUsersByRole(model.RoleAdmin).Return(int64(0), nil)
+		mockRepo.EXPECT().CountDeletedUsersByRole(model.RoleAdmin).Return(int64(0), nil)
+		mockRepo.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			assert.Equal(t, model.RoleAdmin, user.Role)
+			assert.NotEqual(t, "123456", user.Password)
+			return nil
+		})
+
+		srv := newTestUserService(mockRepo)
+		err := srv.BootstrapAdmin(&model.User{Email: "admin@gmail.com", Password: "123456"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should refuse when an admin already exists", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().CountUsersByRole(model.RoleAdmin).Return(int64(1), nil)
+
+		srv := newTestUserService(mockRepo)
+		err := srv.BootstrapAdmin(&model.User{Email: "admin@gmail.com", Password: "123456"})
+		assert.Equal(t, ErrAdminExists, err)
+	})
+
+	t.Run("should refuse when the only admin is deleted", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().CountUsersByRole(model.RoleAdmin).Return(int64(0), nil)
+		mockRepo.EXPECT().CountDeletedUsersByRole(model.RoleAdmin).Return(int64(1), nil)
+
+		srv := newTestUserService(mockRepo)
+		err := srv.BootstrapAdmin(&model.User{Email: "admin@gmail.com", Password: "123456"})
+		assert.Equal(t, ErrAdminDeleted, err)
+	})
+}
+
+var owner = Actor{UserID: 1, Role: model.RoleUser}
+
 func TestService_UpdateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	t.Run("should update user", func(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Role: model.RoleUser}, nil)
 		mockRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil)
 
-		srv := NewUserService(mockRepo)
-		err := srv.UpdateUser(&model.User{
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{
 			Email:    "update@gmail.com",
 			ID:       1,
 			Name:     "update",
@@ -94,12 +175,94 @@ func TestService_UpdateUser(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("should keep the stored password", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Password: "hashed:secret"}, nil)
+		mockRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			assert.Equal(t, "hashed:secret", user.Password)
+			return nil
+		})
+
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{ID: 1, Name: "update", Password: "plaintext"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should keep the stored role", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Role: model.RoleUser}, nil)
+		mockRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			assert.Equal(t, model.RoleUser, user.Role)
+			return nil
+		})
+
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{ID: 1, Name: "update", Role: model.RoleAdmin})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should require verification again when the email changes", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Email: "old@gmail.com", EmailVerified: true}, nil)
+		mockRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			assert.False(t, user.EmailVerified)
+			return nil
+		})
+		verifier := &recordingVerifier{}
+
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), verifier, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, false)
+		err := srv.UpdateUser(owner, &model.User{ID: 1, Email: "new@gmail.com", EmailVerified: true})
+		assert.Nil(t, err)
+
+		assert.Len(t, verifier.users, 1)
+	})
+
+	t.Run("should pass the version that was read to the repository", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Version: 4}, nil)
+		mockRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			assert.Equal(t, uint(4), user.Version)
+			return model.NewError(model.ErrPreconditionFailed, "user has been modified since it was read")
+		})
+
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{ID: 1, Name: "update", Version: 4})
+		assert.True(t, errors.Is(err, model.ErrPreconditionFailed))
+	})
+
+	t.Run("should refuse an update of an outdated version", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Version: 5}, nil)
+
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{ID: 1, Name: "update", Version: 4})
+		assert.Equal(t, model.ErrUserModified, err)
+	})
+
+	t.Run("should return forbidden when updating another account", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(Actor{UserID: 2, Role: model.RoleUser}, &model.User{ID: 1, Name: "update"})
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+
+	t.Run("should return error when user does not exist", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(nil, errors.New("record not found"))
+
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{ID: 1, Name: "update"})
+		assert.NotNil(t, err)
+	})
+
 	t.Run("should return error when update user", func(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Role: model.RoleUser}, nil)
 		mockRepo.EXPECT().UpdateUser(gomock.Any()).Return(errors.New("error"))
 
-		srv := NewUserService(mockRepo)
-		err := srv.UpdateUser(&model.User{
+		srv := newTestUserService(mockRepo)
+		err := srv.UpdateUser(owner, &model.User{
 			Email:    "update@gmail.com",
 			ID:       1,
 			Name:     "update",
@@ -109,29 +272,156 @@ func TestService_UpdateUser(t *testing.T) {
 	})
 }
 
+func TestService_PatchUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stored := func() *model.User {
+		return &model.User{ID: 1, Name: "old", Email: "old@gmail.com", Password: "hashed:secret", Role: model.RoleUser, EmailVerified: true, Version: 3}
+	}
+
+	t.Run("should only change the fields in the patch", func(t *testing.T) {
+		name := "new"
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(stored(), nil)
+		mockRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+
+		srv := newTestUserService(mockRepo)
+		user, err := srv.PatchUser(owner, 1, 3, model.UserPatch{Name: &name})
+		assert.Nil(t, err)
+
+		expected := stored()
+		expected.Name = "new"
+		assert.Equal(t, expected, user)
+	})
+
+	t.Run("should require verification again when the email changes", func(t *testing.T) {
+		email := "new@gmail.com"
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(stored(), nil)
+		mockRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+		verifier := &recordingVerifier{}
+
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), verifier, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, false)
+		user, err := srv.PatchUser(owner, 1, 3, model.UserPatch{Email: &email})
+		assert.Nil(t, err)
+
+		assert.False(t, user.EmailVerified)
+		assert.Len(t, verifier.users, 1)
+	})
+
+	t.Run("should refuse a patch of an outdated version", func(t *testing.T) {
+		name := "new"
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(stored(), nil)
+
+		srv := newTestUserService(mockRepo)
+		_, err := srv.PatchUser(owner, 1, 2, model.UserPatch{Name: &name})
+		assert.Equal(t, model.ErrUserModified, err)
+		assert.True(t, errors.Is(err, model.ErrPreconditionFailed))
+	})
+
+	t.Run("should return forbidden when patching another account", func(t *testing.T) {
+		srv := newTestUserService(mocks.NewMockIRepository(ctrl))
+		_, err := srv.PatchUser(Actor{UserID: 2, Role: model.RoleUser}, 1, 3, model.UserPatch{})
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+
+	t.Run("should return error when user does not exist", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(nil, model.NewError(model.ErrNotFound, "user not found"))
+
+		srv := newTestUserService(mockRepo)
+		_, err := srv.PatchUser(owner, 1, 3, model.UserPatch{})
+		assert.True(t, errors.Is(err, model.ErrNotFound))
+	})
+}
+
 func TestService_DeleteUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	t.Run("should delete user", func(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
-		mockRepo.EXPECT().DeleteUser(uint(1)).Return(nil)
+		mockRepo.EXPECT().DeleteUser(uint(1), uint(3)).Return(nil)
 
-		srv := NewUserService(mockRepo)
-		err := srv.DeleteUser(1)
+		srv := newTestUserService(mockRepo)
+		err := srv.DeleteUser(owner, 1, 3)
 		assert.Nil(t, err)
 	})
 
+	t.Run("should end every session of the deleted user", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().DeleteUser(uint(1), uint(3)).Return(nil)
+		sessions := &recordingRevoker{}
+
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), noopVerifier{}, plainHasher{}, anyPassword{}, testCursors, sessions, false)
+		err := srv.DeleteUser(owner, 1, 3)
+		assert.Nil(t, err)
+
+		assert.Equal(t, []string{""}, sessions.kept)
+	})
+
+	t.Run("should return forbidden when deleting another account", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+
+		srv := newTestUserService(mockRepo)
+		err := srv.DeleteUser(Actor{UserID: 2, Role: model.RoleUser}, 1, 3)
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+
+	t.Run("should consult the access policy", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+
+		srv := NewUserService(mockRepo, denyAll{}, noopVerifier{}, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, false)
+		err := srv.DeleteUser(Actor{UserID: 1, Role: model.RoleAdmin}, 1, 3)
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+
 	t.Run("should return error when delete user", func(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
-		mockRepo.EXPECT().DeleteUser(uint(1)).Return(errors.New("error"))
+		mockRepo.EXPECT().DeleteUser(uint(1), uint(3)).Return(errors.New("error"))
 
-		srv := NewUserService(mockRepo)
-		err := srv.DeleteUser(1)
+		srv := newTestUserService(mockRepo)
+		err := srv.DeleteUser(owner, 1, 3)
 		assert.NotNil(t, err)
 	})
 }
 
+func TestService_RestoreUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := Actor{UserID: 9, Role: model.RoleAdmin}
+
+	t.Run("should restore and return the user", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().RestoreUser(uint(1)).Return(nil)
+		mockRepo.EXPECT().FindUserByID(uint(1)).Return(&model.User{ID: 1, Name: "restored"}, nil)
+
+		srv := newTestUserService(mockRepo)
+		user, err := srv.RestoreUser(admin, 1)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "restored", user.Name)
+	})
+
+	t.Run("should return not found when the user is not deleted", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().RestoreUser(uint(1)).Return(model.NewError(model.ErrNotFound, "user not found"))
+
+		srv := newTestUserService(mockRepo)
+		_, err := srv.RestoreUser(admin, 1)
+		assert.True(t, errors.Is(err, model.ErrNotFound))
+	})
+
+	t.Run("should consult the access policy", func(t *testing.T) {
+		srv := NewUserService(mocks.NewMockIRepository(ctrl), denyAll{}, noopVerifier{}, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, false)
+		_, err := srv.RestoreUser(admin, 1)
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+}
+
 func TestService_AuthenticateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -139,7 +429,7 @@ func TestService_AuthenticateUser(t *testing.T) {
 	t.Run("should return user", func(t *testing.T) {
 		email := "test@gmail.com"
 		password := "123456"
-		hashedPassword := "$2b$12$gBaSY3Lr5QHhLx/yiq/oeu4xw7lUuMNracbFbIJUAFYaY4/Ic0xb."
+		hashedPassword := "hashed:123456"
 		mockRepo := mocks.NewMockIRepository(ctrl)
 		mockRepo.EXPECT().FindUserByEmail(email).Return(&model.User{
 			Email:    "test@gmail.com",
@@ -148,17 +438,80 @@ func TestService_AuthenticateUser(t *testing.T) {
 			Password: hashedPassword,
 		}, nil)
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		user, err := srv.AuthenticateUser(email, password)
 		assert.Nil(t, err)
 
 		assert.Equal(t, user.ID, uint(1))
 	})
 
+	t.Run("should report an unknown email as invalid credentials", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByEmail("nobody@gmail.com").Return(nil, model.NewError(model.ErrNotFound, "user not found"))
+
+		hasher := &recordingHasher{}
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), noopVerifier{}, hasher, anyPassword{}, testCursors, &recordingRevoker{}, false)
+		_, err := srv.AuthenticateUser("nobody@gmail.com", "123456")
+
+		assert.Equal(t, ErrInvalidCredentials, err)
+		assert.True(t, errors.Is(err, model.ErrUnauthorized))
+
+		// The password is still checked against a real hash.
+		assert.Len(t, hasher.verified, 1)
+		assert.True(t, strings.HasPrefix(hasher.verified[0], "hashed:"))
+	})
+
+	t.Run("should refuse an unverified account when verification is required", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{
+			ID:       uint(1),
+			Email:    "test@gmail.com",
+			Password: "hashed:123456",
+		}, nil)
+
+		srv := NewUserService(mockRepo, NewOwnershipPolicy(), noopVerifier{}, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, true)
+		user, err := srv.AuthenticateUser("test@gmail.com", "123456")
+		assert.Equal(t, ErrEmailNotVerified, err)
+
+		assert.Nil(t, user)
+	})
+
+	t.Run("should rehash a password stored with an outdated scheme", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{
+			ID:       uint(1),
+			Email:    "test@gmail.com",
+			Password: "legacy:123456",
+		}, nil)
+		mockRepo.EXPECT().UpdatePassword(uint(1), "hashed:123456").Return(nil)
+
+		srv := newTestUserService(mockRepo)
+		user, err := srv.AuthenticateUser("test@gmail.com", "123456")
+		assert.Nil(t, err)
+
+		assert.Equal(t, "hashed:123456", user.Password)
+	})
+
+	t.Run("should still log in when the rehash cannot be stored", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().FindUserByEmail("test@gmail.com").Return(&model.User{
+			ID:       uint(1),
+			Email:    "test@gmail.com",
+			Password: "legacy:123456",
+		}, nil)
+		mockRepo.EXPECT().UpdatePassword(uint(1), "hashed:123456").Return(errors.New("error"))
+
+		srv := newTestUserService(mockRepo)
+		user, err := srv.AuthenticateUser("test@gmail.com", "123456")
+		assert.Nil(t, err)
+
+		assert.Equal(t, uint(1), user.ID)
+	})
+
 	t.Run("should return error when authenticate user fail", func(t *testing.T) {
 		email := "test@gmail.com"
 		password := "wrong password"
-		hashedPassword := "$2b$12$gBaSY3Lr5QHhLx/yiq/oeu4xw7lUuMNracbFbIJUAFYaY4/Ic0xb."
+		hashedPassword := "hashed:123456"
 		mockRepo := mocks.NewMockIRepository(ctrl)
 		mockRepo.EXPECT().FindUserByEmail(email).Return(&model.User{
 			ID:       uint(1),
@@ -167,7 +520,7 @@ func TestService_AuthenticateUser(t *testing.T) {
 			Password: hashedPassword,
 		}, nil)
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		user, err := srv.AuthenticateUser(email, password)
 		assert.NotNil(t, err)
 
@@ -179,7 +532,7 @@ func TestService_AuthenticateUser(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
 		mockRepo.EXPECT().FindUserByEmail(email).Return(nil, errors.New("error"))
 
-		srv := NewUserService(mockRepo)
+		srv := newTestUserService(mockRepo)
 		user, err := srv.AuthenticateUser(email, "123456")
 		assert.NotNil(t, err)
 
@@ -191,38 +544,203 @@ func TestService_GetUsers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	t.Run("should return users", func(t *testing.T) {
-		mockRepo := mocks.NewMockIRepository(ctrl)
-		mockRepo.EXPECT().FindUsers().Return([]*model.User{
-			{
-				Email:    "test@gmail.com",
-				ID:       1,
-				Name:     "test",
-				Password: "123456",
-			},
-			{
-				Email:    "test2@gmail.com",
-				ID:       2,
-				Name:     "test2",
-				Password: "123456",
-			},
+	t.Run("should apply the paging defaults", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().QueryUsers(model.UserQuery{Page: 1, PerPage: model.DefaultUsersPerPage, Name: "test", Sort: "id"}).Return(&model.UserPage{
+			Users: []*model.User{{ID: 1, Name: "test"}, {ID: 2, Name: "test2"}},
+			Total: 2,
 		}, nil)
 
-		srv := NewUserService(mockRepo)
-		users, err := srv.GetUsers()
+		srv := newTestUserService(mockRepo)
+		page, err := srv.GetUsers(model.UserQuery{Name: "test"})
+		assert.Nil(t, err)
+
+		assert.Len(t, page.Users, 2)
+	})
+
+	t.Run("should cap the page size", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().QueryUsers(model.UserQuery{Page: 3, PerPage: model.MaxUsersPerPage, Sort: "created_at", Descending: true}).Return(&model.UserPage{}, nil)
+
+		srv := newTestUserService(mockRepo)
+		_, err := srv.GetUsers(model.UserQuery{Page: 3, PerPage: 5000, Sort: "created_at", Descending: true})
 		assert.Nil(t, err)
+	})
 
-		assert.Equal(t, len(users), 2)
+	t.Run("should reject a sort field outside the whitelist", func(t *testing.T) {
+		srv := newTestUserService(mocks.NewMockIRepository(ctrl))
+		_, err := srv.GetUsers(model.UserQuery{Sort: "password"})
+
+		assert.True(t, errors.Is(err, model.ErrValidation))
 	})
 
 	t.Run("should return error when get users fail", func(t *testing.T) {
 		mockRepo := mocks.NewMockIRepository(ctrl)
-		mockRepo.EXPECT().FindUsers().Return(nil, errors.New("error"))
+		mockRepo.EXPECT().QueryUsers(gomock.Any()).Return(nil, errors.New("error"))
 
-		srv := NewUserService(mockRepo)
-		users, err := srv.GetUsers()
+		srv := newTestUserService(mockRepo)
+		page, err := srv.GetUsers(model.UserQuery{})
 		assert.NotNil(t, err)
 
-		assert.Nil(t, users)
+		assert.Nil(t, page)
+	})
+}
+
+func TestService_GetUsersAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := []*model.User{
+		{ID: 1, CreatedAt: created},
+		{ID: 2, CreatedAt: created},
+		{ID: 3, CreatedAt: created.Add(time.Second)},
+	}
+
+	t.Run("should return a cursor to the next page when there is one", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().SeekUsers(model.UserQuery{PerPage: 3}, nil).Return(users, nil)
+
+		srv := newTestUserService(mockRepo)
+		page, err := srv.GetUsersAfter(model.UserQuery{PerPage: 2}, "")
+		assert.Nil(t, err)
+
+		assert.Len(t, page.Users, 2)
+		position, err := testCursors.Decode(page.NextCursor, model.UserQuery{})
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), position.ID)
+		assert.True(t, created.Equal(position.CreatedAt))
+	})
+
+	t.Run("should continue after the cursor", func(t *testing.T) {
+		cursor := testCursors.Encode(model.UserCursor{CreatedAt: created, ID: 2}, model.UserQuery{})
+
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().SeekUsers(model.UserQuery{PerPage: 3}, gomock.Any()).DoAndReturn(func(_ model.UserQuery, after *model.UserCursor) ([]*model.User, error) {
+			assert.Equal(t, uint(2), after.ID)
+			return users[2:], nil
+		})
+
+		srv := newTestUserService(mockRepo)
+		page, err := srv.GetUsersAfter(model.UserQuery{PerPage: 2}, cursor)
+		assert.Nil(t, err)
+
+		assert.Len(t, page.Users, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("should cap the page size", func(t *testing.T) {
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().SeekUsers(model.UserQuery{PerPage: model.MaxUsersPerPage + 1}, nil).Return(nil, nil)
+
+		srv := newTestUserService(mockRepo)
+		_, err := srv.GetUsersAfter(model.UserQuery{PerPage: 10000}, "")
+		assert.Nil(t, err)
 	})
+
+	t.Run("should page through users with zero timestamps", func(t *testing.T) {
+		stored := []*model.User{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5, CreatedAt: created}}
+
+		mockRepo := mocks.NewMockIRepository(ctrl)
+		mockRepo.EXPECT().SeekUsers(gomock.Any(), gomock.Any()).DoAndReturn(func(query model.UserQuery, after *model.UserCursor) ([]*model.User, error) {
+			var users []*model.User
+			for _, user := range stored {
+				if after == nil || user.CreatedAt.After(after.CreatedAt) || (user.CreatedAt.Equal(after.CreatedAt) && user.ID > after.ID) {
+					users = append(users, user)
+				}
+			}
+			return users[:min(len(users), query.PerPage)], nil
+		}).AnyTimes()
+
+		srv := newTestUserService(mockRepo)
+		var ids []uint
+		cursor := ""
+		for range stored {
+			page, err := srv.GetUsersAfter(model.UserQuery{PerPage: 2}, cursor)
+			assert.Nil(t, err)
+			for _, user := range page.Users {
+				ids = append(ids, user.ID)
+			}
+			if cursor = page.NextCursor; cursor == "" {
+				break
+			}
+		}
+
+		assert.Equal(t, []uint{1, 2, 3, 4, 5}, ids)
+	})
+
+	t.Run("should reject a cursor issued for other filters", func(t *testing.T) {
+		cursor := testCursors.Encode(model.UserCursor{CreatedAt: created, ID: 2}, model.UserQuery{Name: "te"})
+
+		srv := newTestUserService(mocks.NewMockIRepository(ctrl))
+		_, err := srv.GetUsersAfter(model.UserQuery{Name: "other"}, cursor)
+
+		assert.Equal(t, ErrCursorFilterChanged, err)
+		assert.True(t, errors.Is(err, model.ErrValidation))
+	})
+
+	t.Run("should reject a forged cursor", func(t *testing.T) {
+		srv := newTestUserService(mocks.NewMockIRepository(ctrl))
+		_, err := srv.GetUsersAfter(model.UserQuery{}, "eyJpZCI6MX0.c2lnbmF0dXJl")
+
+		assert.True(t, errors.Is(err, model.ErrValidation))
+	})
+}
+
+type denyAll struct{}
+
+func (denyAll) Authorize(Actor, Action, uint) error {
+	return ErrForbidden
+}
+
+type noopVerifier struct{}
+
+func (noopVerifier) SendVerification(*model.User) error {
+	return nil
+}
+
+func newTestUserService(repo repository.IRepository) *UserService {
+	return NewUserService(repo, NewOwnershipPolicy(), noopVerifier{}, plainHasher{}, anyPassword{}, testCursors, &recordingRevoker{}, false)
+}
+
+type recordingVerifier struct {
+	users []*model.User
+}
+
+func (v *recordingVerifier) SendVerification(user *model.User) error {
+	v.users = append(v.users, user)
+	return nil
+}
+
+// anyPassword is a PasswordValidator that accepts every password.
+type anyPassword struct{}
+
+func (anyPassword) Validate(field, password string, user *model.User) error { return nil }
+
+// plainHasher is a PasswordHasher that prefixes passwords with "hashed:".
+// Hashes prefixed with "legacy:" verify but need a rehash.
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (plainHasher) Verify(hash, password string) (bool, error) {
+	return hash == "hashed:"+password || hash == "legacy:"+password, nil
+}
+
+func (plainHasher) NeedsRehash(hash string) bool {
+	return !strings.HasPrefix(hash, "hashed:")
+}
+
+// recordingHasher is a plainHasher that remembers the hashes it verified
+// passwords against.
+type recordingHasher struct {
+	plainHasher
+	verified []string
+}
+
+func (h *recordingHasher) Verify(hash, password string) (bool, error) {
+	h.verified = append(h.verified, hash)
+	return h.plainHasher.Verify(hash, password)
 }
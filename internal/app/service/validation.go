@@ -0,0 +1,33 @@
+package service
+
+import (
+	"strings"
+
+	"golangHexagonal/internal/app/model"
+)
+
+// FieldViolation describes why one input field was rejected.
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when input breaks one or more rules. It lists
+// every violation, not only the first one.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Field + ": " + violation.Message
+	}
+	return "invalid input: " + strings.Join(messages, "; ")
+}
+
+// Unwrap makes every ValidationError match model.ErrValidation.
+func (e *ValidationError) Unwrap() error {
+	return model.ErrValidation
+}
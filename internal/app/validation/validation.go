@@ -0,0 +1,180 @@
+// Package validation checks input structs against rules declared in their
+// `validate` struct tags, for example:
+//
+//	Email string `json:"email" validate:"required,email,max=254"`
+//
+// Rules are separated by commas:
+//
+//	required   the field is not empty
+//	email      the field is a bare email address
+//	datetime   the field is an RFC 3339 timestamp or a YYYY-MM-DD date
+//	min=N      at least N characters, N elements for slices, or N for numbers
+//	max=N      at most N characters, N elements for slices, or N for numbers
+//	oneof=A B  the field, or each element of a slice, is one of the values
+//
+// Rules other than required are skipped for empty fields. A nil pointer
+// field is absent and skips every rule; otherwise the rules apply to the
+// value it points to. Violations are reported under the JSON name of the
+// field.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golangHexagonal/internal/app/model"
+	"golangHexagonal/internal/app/service"
+)
+
+// Struct validates the struct v points to. It returns a
+// *service.ValidationError listing every violation, or nil.
+func Struct(v any) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: %T is not a struct", v))
+	}
+
+	var violations []service.FieldViolation
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Pointer {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		name := fieldName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			code, message := check(rule, fieldValue)
+			if code == "" {
+				continue
+			}
+			violations = append(violations, service.FieldViolation{Field: name, Code: code, Message: message})
+			if code == "required" {
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &service.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// check applies rule to value and returns the violation code and message,
+// or an empty code when the rule holds.
+func check(rule string, value reflect.Value) (string, string) {
+	name, param, _ := strings.Cut(rule, "=")
+
+	if name == "required" {
+		if value.IsZero() || (value.Kind() == reflect.Slice && value.Len() == 0) {
+			return "required", "is required"
+		}
+		return "", ""
+	}
+	if value.IsZero() {
+		return "", ""
+	}
+
+	switch name {
+	case "email":
+		address, err := mail.ParseAddress(value.String())
+		if err != nil || address.Address != value.String() {
+			return "email", "must be a valid email address"
+		}
+	case "datetime":
+		if _, err := model.ParseTimestamp(value.String()); err != nil {
+			return "datetime", "must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+		}
+	case "min":
+		if size(value) < mustInt(rule, param) {
+			return "min", boundMessage("least", param, value)
+		}
+	case "max":
+		if size(value) > mustInt(rule, param) {
+			return "max", boundMessage("most", param, value)
+		}
+	case "oneof":
+		allowed := strings.Fields(param)
+		for _, item := range items(value) {
+			if !contains(allowed, item) {
+				return "oneof", fmt.Sprintf("must be one of %s", strings.Join(allowed, ", "))
+			}
+		}
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule))
+	}
+	return "", ""
+}
+
+func size(value reflect.Value) int {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int())
+	default:
+		return value.Len()
+	}
+}
+
+func boundMessage(bound, param string, value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be at %s %s characters long", bound, param)
+	case reflect.Slice:
+		return fmt.Sprintf("must have at %s %s items", bound, param)
+	default:
+		return fmt.Sprintf("must be at %s %s", bound, param)
+	}
+}
+
+func items(value reflect.Value) []string {
+	if value.Kind() != reflect.Slice {
+		return []string{value.String()}
+	}
+
+	out := make([]string, value.Len())
+	for i := range out {
+		out[i] = value.Index(i).String()
+	}
+	return out
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func mustInt(rule, param string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		panic(fmt.Sprintf("validation: bad parameter in rule %q", rule))
+	}
+	return n
+}
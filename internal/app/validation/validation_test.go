@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"golangHexagonal/internal/app/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type input struct {
+	Name   string   `json:"name" validate:"required,max=5"`
+	Email  string   `json:"email" validate:"required,email"`
+	Nick   string   `json:"nick,omitempty" validate:"min=2"`
+	Color  string   `json:"color" validate:"oneof=red green"`
+	Scopes []string `json:"scopes" validate:"required,oneof=a b"`
+}
+
+func violations(t *testing.T, err error) map[string]string {
+	var validationErr *service.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	codes := map[string]string{}
+	for _, violation := range validationErr.Violations {
+		codes[violation.Field] = violation.Code
+	}
+	return codes
+}
+
+func TestValidation_Struct(t *testing.T) {
+	t.Run("should accept valid input", func(t *testing.T) {
+		err := Struct(&input{Name: "Jane", Email: "jane@example.com", Color: "red", Scopes: []string{"a", "b"}})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should report every failing field by its JSON name", func(t *testing.T) {
+		err := Struct(&input{Name: "Jane Doe", Email: "Jane <jane@example.com>", Nick: "j", Color: "blue", Scopes: []string{"a", "c"}})
+
+		assert.Equal(t, map[string]string{
+			"name":   "max",
+			"email":  "email",
+			"nick":   "min",
+			"color":  "oneof",
+			"scopes": "oneof",
+		}, violations(t, err))
+	})
+
+	t.Run("should only report required for missing fields", func(t *testing.T) {
+		err := Struct(&input{})
+
+		assert.Equal(t, map[string]string{
+			"name":   "required",
+			"email":  "required",
+			"scopes": "required",
+		}, violations(t, err))
+	})
+
+	t.Run("should count characters rather than bytes", func(t *testing.T) {
+		err := Struct(&input{Name: "Zoë", Email: "zoe@example.com", Scopes: []string{"a"}})
+		assert.Nil(t, err)
+	})
+}
+
+func TestValidation_Numbers(t *testing.T) {
+	type query struct {
+		PerPage int    `json:"per_page" validate:"min=1,max=100"`
+		Since   string `json:"since" validate:"datetime"`
+	}
+
+	t.Run("should bound numbers and check timestamps", func(t *testing.T) {
+		assert.Nil(t, Struct(&query{PerPage: 100, Since: "2024-01-02T15:04:05Z"}))
+		assert.Nil(t, Struct(&query{Since: "2024-01-02"}))
+
+		err := Struct(&query{PerPage: 101, Since: "yesterday"})
+		assert.Equal(t, map[string]string{"per_page": "max", "since": "datetime"}, violations(t, err))
+	})
+}
+
+type patch struct {
+	Name  *string `json:"name" validate:"required,max=5"`
+	Email *string `json:"email" validate:"required,email"`
+}
+
+func TestValidation_Pointers(t *testing.T) {
+	t.Run("should skip absent fields", func(t *testing.T) {
+		assert.Nil(t, Struct(&patch{}))
+	})
+
+	t.Run("should check the value of present fields", func(t *testing.T) {
+		empty, long := "", "Jane Doe"
+		err := Struct(&patch{Name: &long, Email: &empty})
+
+		assert.Equal(t, map[string]string{
+			"name":  "max",
+			"email": "required",
+		}, violations(t, err))
+	})
+}
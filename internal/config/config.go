@@ -1 +1,176 @@
 package config
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+type Config struct {
+	DBUser string `json:"db_user"`
+	DBPass string `json:"db_pass"`
+	DBHost string `json:"db_host"`
+	DBPort string `json:"db_port"`
+	DBName string `json:"db_name"`
+
+	// RevocationStore selects where revoked token IDs are kept: "database"
+	// (default, shared between instances) or "memory".
+	RevocationStore string `json:"revocation_store"`
+
+	JWT        JWTConfig        `json:"jwt"`
+	Auth       AuthConfig       `json:"auth"`
+	Mail       MailConfig       `json:"mail"`
+	Password   PasswordConfig   `json:"password"`
+	Pagination PaginationConfig `json:"pagination"`
+	Users      UsersConfig      `json:"users"`
+}
+
+// UsersConfig sets how long deleted users can be restored before they are
+// purged for good, 30 days by default.
+type UsersConfig struct {
+	DeletedRetention Duration `json:"deleted_retention"`
+}
+
+// PaginationConfig holds the key that signs list cursors, inline or from an
+// environment variable. Without one a random key is made at startup, so
+// cursors stop working when the process restarts.
+type PaginationConfig struct {
+	CursorSecret    string `json:"cursor_secret,omitempty"`
+	CursorSecretEnv string `json:"cursor_secret_env,omitempty"`
+}
+
+// PasswordConfig selects how new password hashes are made. Algorithm is
+// "argon2id" (default) or "bcrypt". Hashes made with the other algorithm, or
+// with other parameters, still verify and are replaced at the next login.
+// Zero parameters take the adapter defaults.
+//
+// The remaining fields configure the policy new passwords must meet.
+// MaxLength is in bytes and defaults to 72, where bcrypt stops reading.
+// MinCharacterClasses counts lower case, upper case, digits and symbols.
+// BreachedPasswordsFile lists known-breached passwords, one per line.
+type PasswordConfig struct {
+	Algorithm       string `json:"algorithm"`
+	BcryptCost      int    `json:"bcrypt_cost"`
+	Argon2Time      uint32 `json:"argon2_time"`
+	Argon2MemoryKiB uint32 `json:"argon2_memory_kib"`
+	Argon2Threads   uint8  `json:"argon2_threads"`
+
+	MinLength             int    `json:"min_length"`
+	MaxLength             int    `json:"max_length"`
+	MinCharacterClasses   int    `json:"min_character_classes"`
+	BreachedPasswordsFile string `json:"breached_passwords_file"`
+}
+
+// AuthConfig holds settings of the account recovery and verification flows.
+// The URLs are the links mailed to users, with the token appended to them.
+// RequireVerifiedEmail refuses logins until the email address is verified.
+// MFAIssuer is the name authenticator apps show next to the account.
+//
+// The Login* settings throttle password guessing: every failure doubles the
+// wait for the next attempt starting at LoginBaseDelay, and an email or
+// client IP is locked out for LoginLockout after LoginMaxFailures or
+// LoginIPMaxFailures failures respectively.
+type AuthConfig struct {
+	PasswordResetURL      string   `json:"password_reset_url"`
+	PasswordResetTTL      Duration `json:"password_reset_ttl"`
+	PasswordResetInterval Duration `json:"password_reset_interval"`
+
+	EmailVerificationURL       string   `json:"email_verification_url"`
+	EmailVerificationTTL       Duration `json:"email_verification_ttl"`
+	VerificationResendInterval Duration `json:"verification_resend_interval"`
+	RequireVerifiedEmail       bool     `json:"require_verified_email"`
+
+	MFAIssuer string `json:"mfa_issuer"`
+
+	LoginMaxFailures   int      `json:"login_max_failures"`
+	LoginIPMaxFailures int      `json:"login_ip_max_failures"`
+	LoginLockout       Duration `json:"login_lockout"`
+	LoginBaseDelay     Duration `json:"login_base_delay"`
+}
+
+// MailConfig selects the mail adapter: "smtp", "file" (appends to FilePath)
+// or "memory".
+type MailConfig struct {
+	Driver   string `json:"driver"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+	FilePath string `json:"file_path"`
+}
+
+// JWTConfig lists every key the service accepts. Tokens are signed with
+// ActiveKeyID; the other keys stay valid for verification so that tokens
+// issued before a rotation keep working until they expire.
+//
+// Issued tokens carry Issuer and every Audience; verified tokens must match
+// the issuer and name at least one of the audiences. Leeway is the clock skew
+// tolerated on exp, nbf and iat. MFAChallengeTTL bounds the time between the
+// password and the TOTP step of a login.
+type JWTConfig struct {
+	ActiveKeyID     string   `json:"active_kid"`
+	Keys            []JWTKey `json:"keys"`
+	Issuer          string   `json:"issuer"`
+	Audience        []string `json:"audience"`
+	AccessTokenTTL  Duration `json:"access_token_ttl"`
+	MFAChallengeTTL Duration `json:"mfa_challenge_ttl"`
+	Leeway          Duration `json:"leeway"`
+}
+
+// JWTKey describes one signing key. HS256 keys take a secret, either inline
+// or from an environment variable; RS256 and EdDSA keys take PEM files. A key
+// with only a public key file can verify tokens but not sign them.
+type JWTKey struct {
+	ID             string `json:"kid"`
+	Algorithm      string `json:"alg"`
+	Secret         string `json:"secret,omitempty"`
+	SecretEnv      string `json:"secret_env,omitempty"`
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	PublicKeyFile  string `json:"public_key_file,omitempty"`
+}
+
+// Duration is a time.Duration read from a string such as "15m" or "30s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// Or returns d, or fallback when d is not set.
+func (d Duration) Or(fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return time.Duration(d)
+}
+
+func Load(path string) (*Config, error) {
+	configFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer configFile.Close()
+
+	var config Config
+	if err := json.NewDecoder(configFile).Decode(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
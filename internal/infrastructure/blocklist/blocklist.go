@@ -0,0 +1,83 @@
+// Package blocklist implements service.PasswordBlocklist from a local list
+// of breached passwords, one per line.
+package blocklist
+
+import (
+	"bufio"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Set keeps each password as the 64-bit FNV-1a hash of its lower-cased form
+// in a sorted slice: eight bytes per entry, looked up by binary search. Hash
+// collisions can make Contains report a password that is not in the list;
+// with 64 bits that is negligible for lists of millions of entries.
+type Set struct {
+	hashes []uint64
+}
+
+// LoadFile reads a list of passwords from path.
+func LoadFile(path string) (*Set, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// Read reads a list of passwords, one per line. Blank lines are skipped.
+func Read(r io.Reader) (*Set, error) {
+	var hashes []uint64
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		hashes = append(hashes, hash(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	return &Set{hashes: dedupe(hashes)}, nil
+}
+
+// Contains implements service.PasswordBlocklist. Matching ignores case.
+func (s *Set) Contains(password string) bool {
+	target := hash(password)
+	i := sort.Search(len(s.hashes), func(i int) bool { return s.hashes[i] >= target })
+	return i < len(s.hashes) && s.hashes[i] == target
+}
+
+// Len returns the number of distinct entries.
+func (s *Set) Len() int {
+	return len(s.hashes)
+}
+
+func hash(password string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(password)))
+	return h.Sum64()
+}
+
+func dedupe(sorted []uint64) []uint64 {
+	if len(sorted) == 0 {
+		return sorted
+	}
+
+	out := sorted[:1]
+	for _, value := range sorted[1:] {
+		if value != out[len(out)-1] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
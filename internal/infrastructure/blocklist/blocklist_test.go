@@ -0,0 +1,27 @@
+package blocklist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("should match listed passwords ignoring case", func(t *testing.T) {
+		set, err := Read(strings.NewReader("password\r\nqwerty123\n\nPassword\n"))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 2, set.Len())
+		assert.True(t, set.Contains("password"))
+		assert.True(t, set.Contains("QWERTY123"))
+		assert.False(t, set.Contains("correct horse battery staple"))
+	})
+
+	t.Run("should handle an empty list", func(t *testing.T) {
+		set, err := Read(strings.NewReader(""))
+		assert.Nil(t, err)
+
+		assert.False(t, set.Contains("password"))
+	})
+}
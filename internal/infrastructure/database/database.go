@@ -12,10 +12,40 @@ func ConnectDB(dsn string) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	err = db.AutoMigrate(&model.User{})
+	if err := backfillUserTimestamps(db); err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(&model.User{}, &model.RefreshToken{}, &model.RevokedToken{}, &model.OneTimeToken{}, &model.RecoveryCode{}, &model.LoginAttempt{}, &model.APIKey{}, &model.Session{})
 	if err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
+
+// userTimestampColumns are the user columns that were added after users
+// already existed.
+var userTimestampColumns = []string{"created_at", "updated_at"}
+
+// backfillUserTimestamps stamps users that have no creation or update time
+// with the current time, so that AutoMigrate can make the columns NOT NULL
+// and every user can be filtered, sorted and paged by them. Databases that
+// do not have the columns yet get them filled by their default instead.
+func backfillUserTimestamps(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&model.User{}) {
+		return nil
+	}
+
+	for _, column := range userTimestampColumns {
+		if !migrator.HasColumn(&model.User{}, column) {
+			continue
+		}
+		err := db.Exec("UPDATE users SET " + column + " = CURRENT_TIMESTAMP(3) WHERE " + column + " IS NULL OR " + column + " < '1000-01-01'").Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+var errMalformedArgon2id = errors.New("hashing: malformed argon2id hash")
+
+// Argon2idParams are the cost parameters of argon2id. Zero fields take the
+// defaults of DefaultArgon2idParams.
+type Argon2idParams struct {
+	Time      uint32
+	MemoryKiB uint32
+	Threads   uint8
+	SaltLen   uint32
+	KeyLen    uint32
+}
+
+// DefaultArgon2idParams follow the second recommended option of RFC 9106
+// (64 MiB, three passes), with two lanes.
+var DefaultArgon2idParams = Argon2idParams{
+	Time:      3,
+	MemoryKiB: 64 * 1024,
+	Threads:   2,
+	SaltLen:   16,
+	KeyLen:    32,
+}
+
+type Argon2id struct {
+	params Argon2idParams
+}
+
+func NewArgon2id(params Argon2idParams) *Argon2id {
+	if params.Time == 0 {
+		params.Time = DefaultArgon2idParams.Time
+	}
+	if params.MemoryKiB == 0 {
+		params.MemoryKiB = DefaultArgon2idParams.MemoryKiB
+	}
+	if params.Threads == 0 {
+		params.Threads = DefaultArgon2idParams.Threads
+	}
+	if params.SaltLen == 0 {
+		params.SaltLen = DefaultArgon2idParams.SaltLen
+	}
+	if params.KeyLen == 0 {
+		params.KeyLen = DefaultArgon2idParams.KeyLen
+	}
+	return &Argon2id{params: params}
+}
+
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.params.Time, a.params.MemoryKiB, a.params.Threads, a.params.KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version,
+		a.params.MemoryKiB, a.params.Time, a.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify recomputes the key with the parameters stored in hash, not the
+// current ones, so that older hashes keep verifying.
+func (a *Argon2id) Verify(hash, password string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Time, params.MemoryKiB, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(candidate, key) == 1, nil
+}
+
+func (a *Argon2id) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, argon2idPrefix)
+}
+
+func (a *Argon2id) Outdated(hash string) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.Time != a.params.Time ||
+		params.MemoryKiB != a.params.MemoryKiB ||
+		params.Threads != a.params.Threads ||
+		uint32(len(salt)) != a.params.SaltLen ||
+		uint32(len(key)) != a.params.KeyLen
+}
+
+// decodeArgon2id parses "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>".
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errMalformedArgon2id
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errMalformedArgon2id
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.MemoryKiB, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, errMalformedArgon2id
+	}
+	if params.MemoryKiB == 0 || params.Time == 0 || params.Threads == 0 {
+		return params, nil, nil, errMalformedArgon2id
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errMalformedArgon2id
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errMalformedArgon2id
+	}
+
+	params.SaltLen = uint32(len(salt))
+	params.KeyLen = uint32(len(key))
+	return params, salt, key, nil
+}
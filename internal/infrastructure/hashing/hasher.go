@@ -0,0 +1,51 @@
+// Package hashing implements service.PasswordHasher. Hashes are stored in a
+// self-describing format: modular crypt for bcrypt ("$2a$10$...") and the PHC
+// string format for argon2id ("$argon2id$v=19$m=...,t=...,p=...$salt$hash"),
+// so the algorithm and parameters of every stored hash can be read back.
+package hashing
+
+import "errors"
+
+var ErrUnknownScheme = errors.New("hashing: hash was not made by a known scheme")
+
+// Scheme is one password hashing algorithm with its current parameters.
+type Scheme interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) (bool, error)
+	// Identifies reports whether hash was made by this algorithm.
+	Identifies(hash string) bool
+	// Outdated reports whether hash, made by this algorithm, uses other
+	// parameters than the current ones.
+	Outdated(hash string) bool
+}
+
+// Hasher makes new hashes with its current scheme and still verifies hashes
+// of the legacy schemes, so that stored hashes can be migrated one login at
+// a time.
+type Hasher struct {
+	current Scheme
+	schemes []Scheme
+}
+
+func NewHasher(current Scheme, legacy ...Scheme) *Hasher {
+	return &Hasher{current: current, schemes: append([]Scheme{current}, legacy...)}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *Hasher) Verify(hash, password string) (bool, error) {
+	for _, scheme := range h.schemes {
+		if scheme.Identifies(hash) {
+			return scheme.Verify(hash, password)
+		}
+	}
+	return false, ErrUnknownScheme
+}
+
+// NeedsRehash reports whether hash should be replaced by a hash of the
+// current scheme and parameters.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	return !h.current.Identifies(hash) || h.current.Outdated(hash)
+}
@@ -0,0 +1,94 @@
+package hashing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2id keeps the tests quick; the parameters are far too weak for
+// real use.
+var fastArgon2id = Argon2idParams{Time: 1, MemoryKiB: 64, Threads: 1}
+
+func TestArgon2id(t *testing.T) {
+	t.Run("should produce a PHC string that verifies", func(t *testing.T) {
+		scheme := NewArgon2id(fastArgon2id)
+		hash, err := scheme.Hash("secret")
+		assert.Nil(t, err)
+
+		assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+		assert.True(t, scheme.Identifies(hash))
+		assert.False(t, scheme.Outdated(hash))
+
+		ok, err := scheme.Verify(hash, "secret")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		ok, err = scheme.Verify(hash, "wrong")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should verify with the stored parameters and report them outdated", func(t *testing.T) {
+		hash, _ := NewArgon2id(fastArgon2id).Hash("secret")
+		stronger := NewArgon2id(Argon2idParams{Time: 2, MemoryKiB: 64, Threads: 1})
+
+		ok, err := stronger.Verify(hash, "secret")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.True(t, stronger.Outdated(hash))
+	})
+
+	t.Run("should reject malformed hashes", func(t *testing.T) {
+		scheme := NewArgon2id(fastArgon2id)
+		for _, hash := range []string{
+			"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+			"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+		} {
+			_, err := scheme.Verify(hash, "secret")
+			assert.NotNil(t, err, hash)
+		}
+	})
+}
+
+func TestHasher(t *testing.T) {
+	t.Run("should verify legacy bcrypt hashes and ask for a rehash", func(t *testing.T) {
+		legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+		assert.Nil(t, err)
+
+		hasher := NewHasher(NewArgon2id(fastArgon2id), NewBcrypt(bcrypt.MinCost))
+
+		ok, err := hasher.Verify(string(legacy), "secret")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.True(t, hasher.NeedsRehash(string(legacy)))
+
+		current, err := hasher.Hash("secret")
+		assert.Nil(t, err)
+		assert.False(t, hasher.NeedsRehash(current))
+	})
+
+	t.Run("should ask for a rehash when the bcrypt cost changed", func(t *testing.T) {
+		hash, err := NewBcrypt(bcrypt.MinCost).Hash("secret")
+		assert.Nil(t, err)
+
+		hasher := NewHasher(NewBcrypt(bcrypt.MinCost + 1))
+		assert.True(t, hasher.NeedsRehash(hash))
+
+		ok, err := hasher.Verify(hash, "wrong")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should refuse hashes of unknown schemes", func(t *testing.T) {
+		hasher := NewHasher(NewBcrypt(bcrypt.MinCost))
+
+		ok, err := hasher.Verify("plaintext", "plaintext")
+		assert.Equal(t, ErrUnknownScheme, err)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,36 @@
+package mail
+
+import "sync"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MemoryMailer records messages instead of sending them. It is meant for
+// tests and local development.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Messages returns a copy of every message sent so far.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}